@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var overrunStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94")).Render
+
+// minAgendaItem is the least time stealTime leaves a later item.
+const minAgendaItem = time.Minute
+
+type agendaItem struct {
+	title   string
+	planned time.Duration
+	actual  time.Duration
+}
+
+// agendaRun timeboxes the items of a meeting one after another.
+type agendaRun struct {
+	items       []agendaItem
+	current     int
+	started     time.Time
+	itemStarted time.Time
+	warned      bool
+	finished    bool
+	summary     string
+	summaryFile string
+}
+
+func (a agendaRun) active() bool {
+	return len(a.items) > 0
+}
+
+// loadAgenda reads items from a file with one item per line, or inline
+// items separated by ';', each written as "<minutes> <title>", e.g.
+// "agenda 5 Intro; 15 Updates; 10 Q&A".
+func loadAgenda(arg string) ([]agendaItem, error) {
+	lines := strings.Split(arg, ";")
+
+	if data, err := os.ReadFile(arg); err == nil {
+		lines = []string{}
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	}
+
+	items := []agendaItem{}
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		minutesStr, title, _ := strings.Cut(line, " ")
+		minutes, err := strconv.Atoi(strings.TrimSuffix(minutesStr, "m"))
+		if err != nil || minutes <= 0 || strings.TrimSpace(title) == "" {
+			return nil, fmt.Errorf("Invalid agenda item %q, use e.g. \"5 Intro\"", line)
+		}
+
+		items = append(items, agendaItem{
+			title:   strings.TrimSpace(title),
+			planned: time.Duration(minutes) * time.Minute,
+		})
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("Empty agenda")
+	}
+	return items, nil
+}
+
+func (m *model) startAgenda(arg string) tea.Cmd {
+	items, err := loadAgenda(arg)
+	if err != nil {
+		m.toast(slog.LevelError, err.Error())
+		return nil
+	}
+
+	now := time.Now()
+	m.agenda = agendaRun{items: items, started: now, itemStarted: now}
+	m.restartClock()
+	return nil
+}
+
+func (a agendaRun) elapsed() time.Duration {
+	return time.Since(a.itemStarted).Round(time.Second)
+}
+
+func (a agendaRun) overrun() time.Duration {
+	return a.elapsed() - a.items[a.current].planned
+}
+
+// totalRemaining is the planned time left for the current and later items.
+func (a agendaRun) totalRemaining() time.Duration {
+	total := -a.elapsed()
+	for _, item := range a.items[a.current:] {
+		total += item.planned
+	}
+	return total
+}
+
+// spareTime is the planned time the items after the current one can give
+// up while keeping minAgendaItem each.
+func (a agendaRun) spareTime() time.Duration {
+	total := time.Duration(0)
+	for _, item := range a.items[a.current+1:] {
+		total += max(item.planned-minAgendaItem, 0)
+	}
+	return total
+}
+
+func (m *model) agendaTick() tea.Cmd {
+	if m.agenda.finished {
+		return nil
+	}
+
+	if m.agenda.overrun() > 0 && !m.agenda.warned {
+		m.agenda.warned = true
+		item := m.agenda.items[m.agenda.current]
+		return tea.Batch(
+			m.tickCmd(),
+			notifyCmd(m.config.Notify, "Agenda item over time", item.title),
+			soundCmd(m.config.Sound),
+		)
+	}
+	return m.tickCmd()
+}
+
+// nextAgendaItem records the current item and moves on, finishing the
+// meeting after the last one.
+func (m *model) nextAgendaItem() {
+	m.agenda.items[m.agenda.current].actual = m.agenda.elapsed()
+	m.agenda.warned = false
+
+	if m.agenda.current+1 >= len(m.agenda.items) {
+		m.finishAgenda()
+		return
+	}
+
+	m.agenda.current++
+	m.agenda.itemStarted = time.Now()
+}
+
+// stealTime extends the current item by its overrun, rounded up to whole
+// minutes, taking the time from the last items first. Later items keep at
+// least minAgendaItem.
+func (m *model) stealTime() {
+	overrun := m.agenda.overrun()
+	if overrun <= 0 {
+		return
+	}
+
+	needed := (overrun + time.Minute - 1).Truncate(time.Minute)
+	needed = min(needed, m.agenda.spareTime())
+
+	items := m.agenda.items
+	items[m.agenda.current].planned += needed
+	for i := len(items) - 1; i > m.agenda.current && needed > 0; i-- {
+		taken := min(max(items[i].planned-minAgendaItem, 0), needed)
+		items[i].planned -= taken
+		needed -= taken
+	}
+	m.agenda.warned = false
+}
+
+func (m *model) finishAgenda() {
+	if m.agenda.current < len(m.agenda.items) && m.agenda.items[m.agenda.current].actual == 0 {
+		m.agenda.items[m.agenda.current].actual = m.agenda.elapsed()
+	}
+
+	m.agenda.finished = true
+	m.agenda.summary = agendaSummary(m.agenda.items, m.agenda.started)
+	m.agenda.summaryFile = fmt.Sprintf("agenda-%s.md", m.agenda.started.Format("2006-01-02-1504"))
+
+	err := os.WriteFile(m.agenda.summaryFile, []byte(m.agenda.summary), 0644)
+	if err != nil {
+		m.toast(slog.LevelError, fmt.Sprintf("Error writing summary: %v", err))
+		m.agenda.summaryFile = ""
+	}
+}
+
+// agendaSummary renders planned vs actual time per item as Markdown.
+func agendaSummary(items []agendaItem, started time.Time) string {
+	summary := fmt.Sprintf("# Meeting summary %s\n\n", started.Format("2006-01-02 15:04"))
+	summary += "| Item | Planned | Actual | Difference |\n"
+	summary += "| --- | ---: | ---: | ---: |\n"
+
+	planned, actual := time.Duration(0), time.Duration(0)
+	for _, item := range items {
+		summary += fmt.Sprintf("| %s | %s | %s | %s |\n",
+			strings.ReplaceAll(item.title, "|", "\\|"),
+			item.planned, item.actual, formatDifference(item.actual-item.planned))
+		planned += item.planned
+		actual += item.actual
+	}
+
+	summary += fmt.Sprintf("| **Total** | %s | %s | %s |\n", planned, actual, formatDifference(actual-planned))
+	return summary
+}
+
+func formatDifference(d time.Duration) string {
+	if d > 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
+
+func (m model) agendaKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.agenda.finished {
+		if key.Matches(msg, m.keys.Stop) {
+			m.agenda = agendaRun{}
+			m.textarea.Reset()
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Next):
+		m.nextAgendaItem()
+	case msg.String() == "t":
+		m.stealTime()
+	case key.Matches(msg, m.keys.Stop):
+		m.finishAgenda()
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) agendaView() string {
+	if m.agenda.finished {
+		saved := ""
+		if m.agenda.summaryFile != "" {
+			saved = fmt.Sprintf("Saved to %s\n", m.agenda.summaryFile)
+		}
+		return fmt.Sprintf("\n%s\n%s%s\n%s",
+			m.agenda.summary, saved, m.toastsView(), helpStyle(" - Press 'x' to close\n"))
+	}
+
+	total := m.agenda.totalRemaining()
+	totalStr := fmt.Sprintf("%s left", total)
+	if total < 0 {
+		totalStr = overrunStyle(fmt.Sprintf("over by %s", -total))
+	}
+
+	rows := ""
+	for i, item := range m.agenda.items {
+		switch {
+		case i < m.agenda.current:
+			rows += fmt.Sprintf("  ✓ %-24s %8s  took %s\n", item.title, item.planned, item.actual)
+		case i == m.agenda.current:
+			left := item.planned - m.agenda.elapsed()
+			leftStr := fmt.Sprintf("%s left", left)
+			if left < 0 {
+				leftStr = overrunStyle(fmt.Sprintf("over by %s", -left))
+			}
+			rows += fmt.Sprintf("  ▶ %-24s %8s  %s\n", item.title, item.planned, leftStr)
+		default:
+			rows += fmt.Sprintf("    %-24s %8s\n", item.title, item.planned)
+		}
+	}
+
+	current := m.agenda.items[m.agenda.current]
+	percent := min(float64(m.agenda.elapsed())/float64(current.planned), 1)
+
+	warning := ""
+	help := " - Press 'n' for the next item\n"
+	if m.agenda.overrun() > 0 {
+		warning = overrunStyle(fmt.Sprintf("⚠ %s is over time", current.title))
+		if m.agenda.spareTime() > 0 {
+			warning += overrunStyle(", press 't' to take the time from later items")
+			help += " - Press 't' to take time from later items\n"
+		}
+	}
+
+	return fmt.Sprintf("\nAgenda: %s\n\n%s\n  %v\n\n%s\n\n%v\n",
+		totalStr,
+		rows,
+		m.progress.ViewAs(percent),
+		warning,
+		helpStyle(help+" - Press 'x' to end the meeting\n - Press 'q' to quit"))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// overrunAgenda runs an agenda whose first item started elapsed ago.
+func overrunAgenda(items []time.Duration, elapsed time.Duration) model {
+	m := initialModel()
+	for i, planned := range items {
+		m.agenda.items = append(m.agenda.items, agendaItem{title: string(rune('A' + i)), planned: planned})
+	}
+	m.agenda.started = time.Now().Add(-elapsed)
+	m.agenda.itemStarted = m.agenda.started
+	return m
+}
+
+func TestStealTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []time.Duration
+		elapsed time.Duration
+		want    []time.Duration
+	}{
+		{
+			name:    "on time",
+			items:   []time.Duration{5 * time.Minute, 10 * time.Minute},
+			elapsed: 4 * time.Minute,
+			want:    []time.Duration{5 * time.Minute, 10 * time.Minute},
+		},
+		{
+			name:    "rounds up to whole minutes from the last item",
+			items:   []time.Duration{5 * time.Minute, 10 * time.Minute, 5 * time.Minute},
+			elapsed: 6*time.Minute + 30*time.Second,
+			want:    []time.Duration{7 * time.Minute, 10 * time.Minute, 3 * time.Minute},
+		},
+		{
+			name:    "spreads over several items",
+			items:   []time.Duration{5 * time.Minute, 3 * time.Minute, 2 * time.Minute},
+			elapsed: 8 * time.Minute,
+			want:    []time.Duration{8 * time.Minute, 1 * time.Minute, 1 * time.Minute},
+		},
+		{
+			name:    "keeps a minute per item",
+			items:   []time.Duration{5 * time.Minute, 2 * time.Minute, 2 * time.Minute},
+			elapsed: 20 * time.Minute,
+			want:    []time.Duration{7 * time.Minute, 1 * time.Minute, 1 * time.Minute},
+		},
+		{
+			name:    "nothing to spare",
+			items:   []time.Duration{5 * time.Minute, 1 * time.Minute},
+			elapsed: 10 * time.Minute,
+			want:    []time.Duration{5 * time.Minute, 1 * time.Minute},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chdirTemp(t)
+			m := overrunAgenda(test.items, test.elapsed)
+			m.stealTime()
+
+			got := []time.Duration{}
+			for _, item := range m.agenda.items {
+				got = append(got, item.planned)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("planned = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAgendaOverrun(t *testing.T) {
+	chdirTemp(t)
+	m := overrunAgenda([]time.Duration{5 * time.Minute, 1 * time.Minute}, 6*time.Minute)
+
+	if got := m.agenda.overrun(); got != time.Minute {
+		t.Errorf("overrun() = %v, want 1m", got)
+	}
+	m.agendaTick()
+	if !m.agenda.warned {
+		t.Error("agendaTick() did not warn about the overrun")
+	}
+
+	m.stealTime()
+	view := m.agendaView()
+	if !strings.Contains(view, "A is over time") {
+		t.Errorf("view does not show the overrun:\n%s", view)
+	}
+	if strings.Contains(view, "press 't'") {
+		t.Errorf("view offers to take time that later items can't give:\n%s", view)
+	}
+
+	m.nextAgendaItem()
+	if view := m.agendaView(); strings.Contains(view, "NaN") || m.agenda.overrun() > 0 {
+		t.Errorf("next item starts over time:\n%s", view)
+	}
+}
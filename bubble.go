@@ -2,25 +2,27 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	padding      = 2
-	maxWidth     = 80
-	workSession  = "Work"
-	breakSession = "Break"
+	padding             = 2
+	maxWidth            = 80
+	workSession         = "Work"
+	breakSession        = "Break"
+	defaultWorkMinutes  = 25
+	defaultBreakMinutes = 5
 )
 
-type tickMsg time.Time
-
 var helpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render
 
 var keys = keyMap{
@@ -30,6 +32,21 @@ var keys = keyMap{
 	Quit: key.NewBinding(
 		key.WithKeys("q", "esc", "ctrl+c"),
 	),
+	Pause: key.NewBinding(
+		key.WithKeys("p"),
+	),
+	Mute: key.NewBinding(
+		key.WithKeys("m"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("n"),
+	),
+	Timer: key.NewBinding(
+		key.WithKeys("t"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("y"),
+	),
 }
 
 func initialModel() model {
@@ -38,7 +55,7 @@ func initialModel() model {
 	ta.Focus()
 
 	ta.Prompt = "┃ "
-	ta.CharLimit = 20
+	ta.CharLimit = 120
 
 	ta.SetWidth(30)
 	ta.SetHeight(2)
@@ -50,20 +67,88 @@ func initialModel() model {
 
 	ta.KeyMap.InsertNewline.SetEnabled(false)
 
-	return model{sessions: loadSessions(), keys: keys,
+	// A broken config falls back to the defaults, and broken templates to
+	// the default templates, reporting the first error.
+	cfg, configErr := loadConfig()
+	templates, err := loadTemplates(cfg.Templates)
+
+	// Sessions and the other stores are loaded by Init so the first frame
+	// never waits for the disk.
+	m := model{sessions: []session{}, config: cfg, days: map[string]dayEntry{}, keys: keys,
+		templates: templates, loading: true, saves: map[string]*saveState{},
+		index:    newSessionIndex(nil),
+		rest:     newRestCalendar(cfg, map[string]string{}),
 		progress: progress.New(progress.WithDefaultGradient()), textarea: ta,
+		logView: viewport.New(maxWidth, messagesHeight),
+	}
+	if configErr != nil {
+		m.toast(slog.LevelError, configErr.Error()+", using the defaults")
+	}
+	if err != nil {
+		m.toast(slog.LevelError, err.Error())
 	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	if m.debug {
+		return tea.Batch(loadStoreCmd, readyCmd, waitForLogCmd)
+	}
+	return tea.Batch(loadStoreCmd, readyCmd)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	state := m.status().State
+	next, cmd := m.update(msg)
+	m = next.(model)
+	cmd = tea.Batch(cmd, m.syncClock(), m.syncToasts())
+	if next := m.status().State; next != state {
+		logger.Info("timer state changed", "from", state, "to", next, "type", m.sessionType)
+	}
+	m.service.publish(m.status())
+	return m, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.textarea, _ = m.textarea.Update(msg)
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.inSession {
+		if m.showMessages {
+			if key.Matches(msg, m.keys.Stop) {
+				m.showMessages = false
+				m.textarea.Reset()
+				return m, nil
+			}
+			m.logView, _ = m.logView.Update(msg)
+			return m, nil
+		}
+
+		if m.agenda.active() {
+			return m.agendaKey(msg)
+		}
+
+		if m.inSession && m.askingPosture {
+			return m.postureKey(msg)
+		}
+
+		if m.inSession && (m.askingIntent || m.reviewing) {
+			return m.intentKey(msg)
+		}
+
+		if m.inSession && m.prompting {
+			switch msg.Type {
+			case tea.KeyCtrlC:
+				return m, tea.Quit
+			case tea.KeyEsc:
+				m.prompting = false
+				m.textarea.Reset()
+				return m, nil
+			case tea.KeyEnter:
+				m.prompting = false
+			default:
+				return m, nil
+			}
+		} else if m.inSession {
 			if m.opening || m.closing {
 				return m, nil
 			}
@@ -71,112 +156,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, m.keys.Stop):
 
 				if m.inSession {
-					m.inSession = false
-					m.textarea.Reset()
+					return m, m.stopSession()
 				}
 				return m, nil
+			case key.Matches(msg, m.keys.Mute):
+				return m, m.toggleMute()
+			case key.Matches(msg, m.keys.Pause):
+				if m.paused {
+					return m, m.resumeSession()
+				}
+				return m, m.pauseSession()
 			case key.Matches(msg, m.keys.Quit):
 				return m, tea.Quit
+			case key.Matches(msg, m.keys.Timer):
+				m.prompting = true
+				m.textarea.SetValue("t ")
+				return m, nil
+			case msg.String() == ":":
+				m.prompting = true
+				m.textarea.Reset()
+				return m, nil
+			case m.sequence.active() && key.Matches(msg, m.keys.Next):
+				return m, m.jumpToStep(m.sequence.current + 1)
+			case m.sequence.active() && len(msg.Runes) == 1 && msg.Runes[0] >= '1' && msg.Runes[0] <= '9':
+				return m, m.jumpToStep(int(msg.Runes[0] - '1'))
 			}
 		}
 
-		if m.showSession && key.Matches(msg, m.keys.Stop) {
+		if (m.showSession || m.showIntents || m.showCalendar) && key.Matches(msg, m.keys.Copy) {
+			return m, m.copySummary()
+		}
+
+		if (m.showSession || m.showIntents || m.showCalendar) && key.Matches(msg, m.keys.Stop) {
 			m.showSession = false
+			m.showIntents = false
+			m.showCalendar = false
 			m.textarea.Reset()
 			return m, nil
 		}
 
-		m.err = ""
+		if m.normalMode() && !m.inSession && !m.commandLine {
+			return m.normalKey(msg)
+		}
+
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyEsc:
+			if m.commandLine {
+				m.commandLine = false
+				m.textarea.Reset()
+				return m, nil
+			}
+			return m, tea.Quit
+		case tea.KeyCtrlC:
 			return m, tea.Quit
-		case tea.KeyType('q'):
-			return m, nil
 		case tea.KeyEnter:
 			command := m.textarea.Value()
 			m.textarea.Reset()
+			m.commandLine = false
 
-			switch {
-			case command == "q":
-				return m, tea.Quit
-			case strings.HasPrefix(command, "s"):
-				numOfMinutes, ok := checkValidMinute(&m, command)
-				if !ok {
-					return m, nil
-				}
-
-				if numOfMinutes == 0 {
-					numOfMinutes = 25
-				}
-
-				if !m.inSession {
-					m.startTime = time.Now()
-					m.sessionType = workSession
-					m.timerDuration = time.Duration(numOfMinutes) * time.Minute
-					m.remainingTime = m.timerDuration + 3*time.Second
-					m.percent = 0
-					m.inSession = true
-					m.opening = true
-					m.closing = false
-					return m, tickCmd()
-				} else {
-					return m, nil
-				}
-			case strings.HasPrefix(command, "b"):
-				numOfMinutes, ok := checkValidMinute(&m, command)
-				if !ok {
-					return m, nil
-				}
-
-				if numOfMinutes == 0 {
-					numOfMinutes = 5
-				}
-
-				if !m.inSession {
-					m.startTime = time.Now()
-					m.sessionType = breakSession
-					m.timerDuration = time.Duration(numOfMinutes) * time.Minute
-					m.remainingTime = m.timerDuration + 3*time.Second
-					m.percent = 0
-					m.inSession = true
-					m.opening = true
-					m.closing = false
-					return m, tickCmd()
-				} else {
-					return m, nil
-				}
-			case strings.HasPrefix(command, "l"):
-				if command == "l" {
-					m.printDifferentDate = false
-					m.showSession = true
-				} else {
-					spacing := command[1:]
-					if !strings.HasPrefix(spacing, " ") {
-						m.err = "Invalid command"
-						return m, nil
-					}
-
-					dateStr := strings.TrimSpace(command[2:])
-
-					date, err := time.Parse(time.DateOnly, dateStr)
-					if err != nil {
-						m.err = "Invalid date format"
-						return m, nil
-					}
-					m.printDifferentDate = true
-					m.datePrint = date
-					m.showSession = true
-				}
-
-				return m, nil
-
-			default:
-				if !m.inSession {
-					showHelper()
-				}
-				m.err = "Invalid command"
-				return m, nil
-			}
+			return m.execCommand(command)
 		default:
 			if !m.inSession {
 				showHelper()
@@ -189,71 +227,328 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.progress.Width > maxWidth {
 			m.progress.Width = maxWidth
 		}
+		m.logView.Width = msg.Width
+		m.logView.Height = max(msg.Height-messagesChrome, 1)
 		return m, nil
 
+	case sideTickMsg:
+		if msg.id != m.sideTickID {
+			return m, nil
+		}
+		return m, m.sideTick(msg.now)
+
+	case tea.FocusMsg:
+		return m, m.focusChanged(true)
+
+	case tea.BlurMsg:
+		return m, m.focusChanged(false)
+
 	case tickMsg:
-		if !m.inSession {
+		if msg.id != m.tickID || !m.ticking {
 			return m, nil
 		}
 
-		m.remainingTime -= 1 * time.Second
+		if m.agenda.active() {
+			return m, m.agendaTick()
+		}
+
+		m.remainingTime = m.untilDeadline()
 
 		if m.opening {
 			if m.remainingTime.Milliseconds() <= m.timerDuration.Milliseconds() {
 				m.opening = false
+				return m, tea.Batch(m.tickCmd(), m.emit(eventStart), m.postureReminder())
 			}
 
-			return m, tickCmd()
+			return m, m.tickCmd()
 		}
 
-		if m.remainingTime.Seconds() <= -4 {
-			m.closing = false
-			m.inSession = false
-
-			if m.sessionType == workSession {
-				endSession := time.Now()
-				m.sessions = append(m.sessions, session{StartTime: m.startTime,
-					EndTime: endSession, Duration: m.timerDuration})
-				err := saveSessions(m.sessions)
-				if err != nil {
-					m.err = err.Error()
-				}
+		if m.remainingTime <= -closingTime {
+			if m.needsReview() {
+				// The session is saved now and its outcome added after
+				// the review, which may never come.
+				m.reviewing = true
+				m.textarea.Reset()
+				return m, m.recordSession()
 			}
 
-			return m, nil
+			return m, m.finishSession()
 		}
 
 		if m.remainingTime.Seconds() <= 0 {
-			m.closing = true
-			return m, tickCmd()
+			if !m.closing {
+				m.closing = true
+				m.completedAt = time.Now()
+				return m, tea.Batch(m.tickCmd(), m.emit(eventComplete))
+			}
+			return m, m.tickCmd()
 		}
 
 		m.percent = 1 - float64(m.remainingTime.Milliseconds())/float64(m.timerDuration.Milliseconds())
 
-		return m, tickCmd()
+		return m, tea.Batch(m.tickCmd(), m.fireMarks(), m.remainingGaugeCmd(time.Now()))
+
+	case integrationErrMsg:
+		m.toast(slog.LevelError, msg.err.Error())
+		return m, nil
+
+	case toastExpiredMsg:
+		m.expireToasts()
+		return m, nil
+
+	case logLineMsg:
+		return m, waitForLogCmd
+
+	case copiedMsg:
+		m.toast(slog.LevelInfo, fmt.Sprintf("Copied the %s", msg.what))
+		return m, nil
+
+	case storeLoadedMsg:
+		return m, m.storeLoaded(msg)
+
+	case savedMsg:
+		return m, m.saved(msg)
+
+	case retrySaveMsg:
+		return m, m.retrySave(msg)
+
+	case retryLoadMsg:
+		return m, loadStoreCmd
+
+	case readyMsg:
+		sdNotify("READY=1")
+		return m, nil
+
+	case controlMsg:
+		logger.Debug("control request", "action", msg.action)
+		cmd, err := m.control(msg)
+		msg.reply <- controlReply{status: m.status(), err: err}
+		return m, cmd
+
+	default:
+		return m, nil
+	}
+}
+
+// execCommand runs a command typed on the command line.
+func (m model) execCommand(command string) (tea.Model, tea.Cmd) {
+	switch {
+	case command == "q":
+		return m, tea.Quit
+	case command == "m":
+		return m, m.toggleMute()
+	case strings.HasPrefix(command, "s"):
+		if m.inSession {
+			return m, nil
+		}
+
+		m.preset = ""
+		m.project = ""
+		m.tags = nil
+		command = parseProject(&m, command)
+		numOfMinutes, ok := checkValidMinute(&m, command)
+		if !ok {
+			return m, nil
+		}
+
+		if numOfMinutes == 0 {
+			numOfMinutes = defaultWorkMinutes
+		}
+
+		return m, m.startSession(workSession, numOfMinutes)
+	case strings.HasPrefix(command, "b"):
+		if m.inSession {
+			return m, nil
+		}
+
+		m.preset = ""
+		numOfMinutes, ok := checkValidMinute(&m, command)
+		if !ok {
+			return m, nil
+		}
+
+		if numOfMinutes == 0 {
+			numOfMinutes = defaultBreakMinutes
+		}
+
+		return m, m.startSession(breakSession, numOfMinutes)
+	case strings.HasPrefix(command, "run "):
+		if m.inSession {
+			return m, nil
+		}
+
+		m.project = ""
+		m.tags = nil
+		command = parseProject(&m, command)
+		return m, m.startSequence(strings.TrimSpace(command[3:]))
+	case command == "i":
+		m.showIntents = true
+		return m, nil
+	case command == "log":
+		m.openMessages()
+		return m, nil
+	case strings.HasPrefix(command, "plan "):
+		plan, err := addPlanItem(m.plan, command[5:], false)
+		if err != nil {
+			m.toast(slog.LevelError, err.Error())
+			return m, nil
+		}
+		m.plan = plan
+		return m, m.queueSave(planFile)
+	case strings.HasPrefix(command, "label "):
+		label, date, err := parseDayCommand(command[6:])
+		if err != nil {
+			m.toast(slog.LevelError, err.Error())
+			return m, nil
+		}
+
+		toggleDayLabel(m.days, date, label)
+		return m, m.queueSave(daysFile)
+	case strings.HasPrefix(command, "journal "):
+		addDayNote(m.days, time.Now(), strings.TrimSpace(command[8:]))
+		return m, m.queueSave(daysFile)
+	case command == "c" || strings.HasPrefix(command, "c "):
+		month := time.Now()
+		if command != "c" {
+			date, err := time.ParseInLocation("2006-01", strings.TrimSpace(command[2:]), time.Local)
+			if err != nil {
+				m.toast(slog.LevelWarn, "Invalid month format")
+				return m, nil
+			}
+			month = date
+		}
+		m.calendarMonth = month
+		m.showCalendar = true
+		return m, nil
+	case strings.HasPrefix(command, "t "):
+		return m, m.handleSideTimer(command)
+	case strings.HasPrefix(command, "agenda "):
+		if m.inSession {
+			return m, nil
+		}
+
+		return m, m.startAgenda(strings.TrimSpace(command[6:]))
+	case strings.HasPrefix(command, "l"):
+		if command == "l" {
+			m.printDifferentDate = false
+			m.showSession = true
+		} else {
+			spacing := command[1:]
+			if !strings.HasPrefix(spacing, " ") {
+				m.toast(slog.LevelWarn, "Invalid command")
+				return m, nil
+			}
+
+			dateStr := strings.TrimSpace(command[2:])
+
+			date, err := time.Parse(time.DateOnly, dateStr)
+			if err != nil {
+				m.toast(slog.LevelWarn, "Invalid date format")
+				return m, nil
+			}
+			m.printDifferentDate = true
+			m.datePrint = date
+			m.showSession = true
+		}
+
+		return m, nil
 
 	default:
+		if !m.inSession {
+			showHelper()
+		}
+		m.toast(slog.LevelWarn, "Invalid command")
 		return m, nil
 	}
 }
 
 func (m model) View() string {
+	if m.showMessages {
+		return m.messagesView()
+	}
+
+	if m.agenda.active() {
+		return m.agendaView()
+	}
+
+	if m.showIntents {
+		return fmt.Sprintf("\n%s\n%s",
+			intentReport(m.index, intentReportWeeks, time.Now()),
+			helpStyle(" - Press 'y' to copy a summary, 'x' to stop\n"))
+	}
+
+	if m.showCalendar {
+		return fmt.Sprintf("\n%s\n%s",
+			calendarView(m.index, m.days, m.rest, m.calendarMonth),
+			helpStyle(" - Press 'y' to copy a summary, 'x' to stop\n"))
+	}
+
 	if m.showSession {
 		return fmt.Sprintf("\n%s\n%s",
-			printSessions(m.sessions, m.printDifferentDate, m.datePrint),
-			helpStyle(" - Press 'x' to stop\n"))
+			printSessions(m.index, m.days, m.templates, m.printDifferentDate, m.datePrint),
+			helpStyle(" - Press 'y' to copy a summary, 'x' to stop\n"))
 	}
 
 	if !m.inSession {
+		help := showHelper()
+		input := m.textarea.View()
+		if m.normalMode() {
+			if !m.showHelp {
+				help = normalHelp
+			}
+			if !m.commandLine {
+				input = ""
+			}
+		}
+
+		goal := ""
+		if !m.loading {
+			goal = goalLine(m.index, m.rest, m.config.DailyGoal)
+		}
+
 		return fmt.Sprintf(
-			"\n%s\n%s\n\n%s\n\n",
-			showHelper(),
-			m.textarea.View(),
-			m.err,
+			"\n%s\n%s\n%s\n\n%s\n\n%s\n%s %s\n%s",
+			help,
+			goal,
+			input,
+			m.toastsView(),
+			m.sideTimersView(),
+			m.modeFooter(),
+			m.saveStatusView(),
+			m.logPaneView(),
 		)
 	}
+	overview := ""
+	if m.sequence.active() {
+		overview = m.sequence.overview() + "\n"
+	}
+
+	if m.askingPosture {
+		return fmt.Sprintf("%s\nAre you sitting or standing for this %s session?\n\n%s\n",
+			overview,
+			m.sessionType,
+			helpStyle(" - Press 's' for sitting, 't' for standing, Esc to skip"))
+	}
+
+	if m.askingIntent {
+		return fmt.Sprintf("%s\nWhat do you intend to get done in this %s session?\n\n%s\n\n%s\n",
+			overview,
+			m.sessionType,
+			m.textarea.View(),
+			helpStyle(" - Press Enter to start, Esc to skip"))
+	}
+
+	if m.reviewing {
+		return fmt.Sprintf("%s\nYou have completed one %s session. Did you achieve your intent?\n\n  %s\n\n%s\n",
+			overview,
+			m.sessionType,
+			intentStyle(m.intent),
+			helpStyle(" - Press 'y' for yes, 'p' for partly, 'n' for no, Esc to skip"))
+	}
+
 	if m.opening {
-		return fmt.Sprintf("Ready to start new %s session for %.0f minutes in %d seconds...",
+		return fmt.Sprintf("%sReady to start new %s session for %.0f minutes in %d seconds...",
+			overview,
 			m.sessionType,
 			m.timerDuration.Minutes(),
 			int(m.remainingTime.Seconds()-m.timerDuration.Seconds()))
@@ -269,15 +564,218 @@ func (m model) View() string {
 			workSession)
 	}
 
-	return fmt.Sprintf("\n%s Timer: %s left\n\n  %v\n\n\n%v\n",
-		m.sessionType,
+	title := m.sessionType + " Timer"
+	if m.sessionType == workSession {
+		if m.project != "" {
+			title += " @" + m.project
+		}
+		for _, tag := range m.tags {
+			title += " #" + tag
+		}
+	}
+	if m.highlight != "" && time.Now().Before(m.highlightUntil) {
+		title = markStyle(title + " · " + m.highlight)
+	}
+
+	status := ""
+	help := " - Press 'p' to pause\n"
+	if m.paused {
+		status = " (paused)"
+		help = " - Press 'p' to resume\n"
+	}
+	if m.muted {
+		status += " 🔇"
+		help += " - Press 'm' to unmute\n"
+	} else {
+		help += " - Press 'm' to mute\n"
+	}
+
+	if m.sequence.active() {
+		help += " - Press 1-9 to jump to a step, 'n' for the next one\n"
+	}
+	help += " - Press 't' to add a side timer, ':' for the command line\n"
+
+	below := ""
+	if timers := m.sideTimersView(); timers != "" {
+		below += "  " + timers + "\n"
+	}
+	if m.prompting {
+		below += "\n" + m.textarea.View() + "\n"
+		help = " - Press Enter to run, Esc to cancel\n"
+	}
+	if toasts := m.toastsView(); toasts != "" {
+		below += toasts + "\n"
+	}
+
+	if m.intent != "" {
+		overview += "\n" + intentStyle("🎯 "+m.intent) + "\n"
+	}
+	if m.config.Posture != "" && m.posture != "" {
+		if m.sessionType == workSession {
+			status += fmt.Sprintf(" · %s", m.posture)
+		} else {
+			status += fmt.Sprintf(" · switch to %s next", otherPosture(m.posture))
+		}
+	}
+
+	return fmt.Sprintf("\n%s%s: %s left%s\n\n  %v\n  %v\n%s\n%v\n%s\n",
+		overview,
+		title,
 		m.remainingTime,
+		status,
 		m.progress.ViewAs(m.percent),
-		helpStyle(" - Press 'x' to stop\n - Press 'q' to quit"))
+		helpStyle(markerRow(m.marks, m.timerDuration, m.progress.Width)),
+		below,
+		helpStyle(help+" - Press 'x' to stop\n - Press 'q' to quit"),
+		m.modeFooter()+" "+m.saveStatusView()+"\n"+m.logPaneView())
+}
+
+func (m *model) startSession(sessionType string, numOfMinutes int) tea.Cmd {
+	m.startTime = time.Now()
+	m.sessionType = sessionType
+	m.timerDuration = time.Duration(numOfMinutes) * time.Minute
+	m.remainingTime = m.timerDuration + 3*time.Second
+	m.percent = 0
+	m.inSession = true
+	m.opening = true
+	m.closing = false
+	m.paused = false
+	m.pauses = 0
+	m.gaugeSentAt = time.Time{}
+	m.highlight = ""
+	m.intent = ""
+	m.reviewing = false
+	m.askingIntent = sessionType == workSession && m.config.AskIntent && !m.headless
+	m.askingPosture = false
+	m.prompting = false
+	m.textarea.Reset()
+	if sessionType == workSession {
+		m.choosePosture()
+	}
+
+	marks, err := parseMarks(m.markSpecs(), m.timerDuration)
+	if err != nil {
+		m.toast(slog.LevelError, err.Error())
+	}
+	m.marks = marks
+
+	m.restartClock()
+	return nil
+}
+
+// fireMarks emits an event for each mark the remaining time has reached.
+func (m *model) fireMarks() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for i := range m.marks {
+		if m.marks[i].fired || m.remainingTime > m.marks[i].remaining {
+			continue
+		}
+
+		m.marks[i].fired = true
+		m.highlight = m.marks[i].label
+		m.highlightUntil = time.Now().Add(markHighlight)
+
+		event := m.event(eventMark)
+		event.Mark = m.marks[i].label
+		cmds = append(cmds, m.dispatch(event))
+	}
+	return tea.Batch(cmds...)
+}
+
+// finishSession saves the completed session and moves on to the next step
+// of a running sequence.
+func (m *model) finishSession() tea.Cmd {
+	save := m.recordSession()
+	return tea.Batch(save, m.endSession())
+}
+
+// recordSession saves the completed session. Breaks are only kept as part
+// of a sequence.
+func (m *model) recordSession() tea.Cmd {
+	if m.sessionType != workSession && !m.sequence.active() {
+		return nil
+	}
+
+	completed := session{StartTime: m.startTime,
+		EndTime: m.completedAt, Duration: m.timerDuration, Pauses: m.pauses,
+		Project: m.project, Tags: m.tags, Type: m.sessionType, Intent: m.intent}
+	// The posture is remembered across sessions, but only recorded
+	// while posture tracking is on.
+	if m.sessionType == workSession && m.config.Posture != "" {
+		completed.Posture = m.posture
+	}
+	if m.sequence.active() {
+		completed.Sequence = m.sequence.id
+		completed.Step = m.sequence.current + 1
+	}
+
+	m.sessions = append(m.sessions, completed)
+	m.index.add(completed)
+	return m.queueSave(sessionsFile)
+}
+
+// reviewSession records how well the intent of the session saved last was
+// achieved.
+func (m *model) reviewSession(outcome string) tea.Cmd {
+	last := len(m.sessions) - 1
+	old := m.sessions[last]
+	reviewed := old
+	reviewed.Outcome = outcome
+
+	m.sessions[last] = reviewed
+	m.index.update(old, reviewed)
+	return m.queueSave(sessionsFile)
+}
+
+// endSession leaves the completed session and moves on to the next step of
+// a running sequence.
+func (m *model) endSession() tea.Cmd {
+	m.closing = false
+	m.inSession = false
+	m.reviewing = false
+	m.prompting = false
+	m.textarea.Reset()
+
+	if m.sequence.active() {
+		return m.nextStep()
+	}
+	return nil
+}
+
+func (m *model) stopSession() tea.Cmd {
+	started := !m.opening
+	m.sequence = sequenceRun{}
+	m.askingIntent = false
+	m.askingPosture = false
+	m.reviewing = false
+	m.inSession = false
+	m.opening = false
+	m.closing = false
+	m.paused = false
+	m.prompting = false
+	m.textarea.Reset()
+
+	if !started {
+		return nil
+	}
+	return m.emit(eventAbandon)
+}
+
+func (m *model) toggleMute() tea.Cmd {
+	m.muted = !m.muted
+	if m.muted {
+		speech.silence()
+	}
+	return nil
+}
+
+func (m *model) pauseSession() tea.Cmd {
+	m.paused = true
+	m.pauses++
+	return m.emit(eventPause)
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg {
-		return tickMsg{}
-	})
+func (m *model) resumeSession() tea.Cmd {
+	m.paused = false
+	return m.emit(eventResume)
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSessionEndClosesPrompt(t *testing.T) {
+	chdirTemp(t)
+
+	for name, end := range map[string]func(m *model){
+		"finish": func(m *model) { m.finishSession() },
+		"stop":   func(m *model) { m.stopSession() },
+		"start":  func(m *model) { m.startSession(breakSession, 5) },
+	} {
+		m := initialModel()
+		m.loading = false
+		m.startSession(workSession, 25)
+		m.opening = false
+		m.prompting = true
+		m.textarea.SetValue("t 10 tea")
+
+		end(&m)
+		if m.prompting || m.textarea.Value() != "" {
+			t.Errorf("after %s: prompting = %v, input = %q, want a closed, empty prompt",
+				name, m.prompting, m.textarea.Value())
+		}
+	}
+}
+
+// isQuit reports whether cmd, or one of a batch, quits the program.
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	switch msg := cmd().(type) {
+	case tea.QuitMsg:
+		return true
+	case tea.BatchMsg:
+		return slices.ContainsFunc(msg, isQuit)
+	}
+	return false
+}
+
+func TestCtrlCQuitsFromSideTimerPrompt(t *testing.T) {
+	chdirTemp(t)
+
+	m := initialModel()
+	m.startSession(workSession, 25)
+	m.opening = false
+	m.prompting = true
+	if _, cmd := m.update(tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("ctrl+c does not quit while the side timer prompt is open")
+	}
+}
+
+func TestCtrlCQuitsWhileAskingIntent(t *testing.T) {
+	chdirTemp(t)
+
+	m := initialModel()
+	m.config.AskIntent = true
+	m.startSession(workSession, 25)
+	if !m.askingIntent {
+		t.Fatal("not asking for an intent")
+	}
+	if _, cmd := m.update(tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("ctrl+c does not quit while asking for an intent")
+	}
+
+	m.askingIntent = false
+	m.reviewing = true
+	if _, cmd := m.update(tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("ctrl+c does not quit while reviewing an intent")
+	}
+}
+
+func TestCtrlCQuitsWhileAskingPosture(t *testing.T) {
+	chdirTemp(t)
+
+	m := initialModel()
+	m.config.Posture = "ask"
+	m.startSession(workSession, 25)
+	if !m.askingPosture {
+		t.Fatal("not asking for a posture")
+	}
+	if _, cmd := m.update(tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("ctrl+c does not quit while asking for a posture")
+	}
+}
+
+func TestPostureRecordedOnlyWhenTracked(t *testing.T) {
+	chdirTemp(t)
+
+	for _, setting := range []string{"", "alternate"} {
+		m := initialModel()
+		m.loading = false
+		m.config.Posture = setting
+		m.posture = sitting
+		m.startSession(workSession, 25)
+		m.finishSession()
+
+		got := m.sessions[len(m.sessions)-1].Posture
+		if tracked := got != ""; tracked != (setting != "") {
+			t.Errorf("posture %q recorded with posture setting %q", got, setting)
+		}
+	}
+}
+
+// reviewing returns a model whose work session just ended and now asks
+// whether its intent was achieved.
+func reviewing(t *testing.T) model {
+	t.Helper()
+	m := initialModel()
+	m.loading = false
+	m.startSession(workSession, 25)
+	m.intent = "ship the parser"
+	m.opening = false
+	m.ticking = true
+	m.deadline = time.Now().Add(-closingTime - time.Second)
+
+	next, _ := m.update(tickMsg{id: m.tickID})
+	m = next.(model)
+	if !m.reviewing {
+		t.Fatal("not reviewing the intent")
+	}
+	return m
+}
+
+func TestCtrlCDuringReviewKeepsSession(t *testing.T) {
+	chdirTemp(t)
+
+	next, cmd := reviewing(t).update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !isQuit(cmd) {
+		t.Fatal("ctrl+c does not quit while reviewing an intent")
+	}
+	if err := next.(model).flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := loadSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].Intent != "ship the parser" || sessions[0].Outcome != "" {
+		t.Errorf("saved %+v, want the reviewed session without an outcome", sessions)
+	}
+}
+
+func TestReviewUpdatesSavedSession(t *testing.T) {
+	chdirTemp(t)
+
+	next, _ := reviewing(t).update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m := next.(model)
+	if m.inSession || len(m.sessions) != 1 || m.sessions[0].Outcome != "partly" {
+		t.Fatalf("after the review: in session = %v, sessions = %+v", m.inSession, m.sessions)
+	}
+
+	today := m.sessions[0].completedAt()
+	if got := m.index.totals(today).Outcomes; got != (outcomeCounts{Partly: 1}) {
+		t.Errorf("day outcomes = %+v, want one partly", got)
+	}
+	if got := m.index.totals(today).Pomodoros; got != 1 {
+		t.Errorf("day pomodoros = %d, want 1 after editing the outcome", got)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	focusDayStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575")).Render
+	restDayStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render
+)
+
+// calendarView draws a month with the number of pomodoros per day and the
+// first letter of each labelled day's first label, followed by a legend.
+// Rest days are dimmed.
+func calendarView(index sessionIndex, days map[string]dayEntry, rest restCalendar, month time.Time) string {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	result := fmt.Sprintf("%s\n\n", first.Format("January 2006"))
+	result += " Mo    Tu    We    Th    Fr    Sa    Su\n"
+
+	// Weeks start on Monday.
+	offset := (int(first.Weekday()) + 6) % 7
+	result += strings.Repeat("      ", offset)
+
+	legend := map[string][]string{}
+	restDays := []string{}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		key := day.Format(time.DateOnly)
+		pomodoros := index.pomodoros(day)
+
+		count := "  "
+		if pomodoros > 9 {
+			count = "+ "
+		} else if pomodoros > 0 {
+			count = fmt.Sprintf("%d ", pomodoros)
+		}
+
+		mark := " "
+		if entry, ok := days[key]; ok && len(entry.Labels) > 0 {
+			mark = labelMark(entry.Labels[0])
+			for _, label := range entry.Labels {
+				legend[label] = append(legend[label], fmt.Sprint(day.Day()))
+			}
+		}
+
+		reason := rest.restReason(day)
+		cell := fmt.Sprintf("%3d", day.Day())
+		if pomodoros > 0 {
+			cell = focusDayStyle(cell)
+		} else if reason != "" {
+			cell = restDayStyle(cell)
+		}
+		if reason != "" && reason != day.Weekday().String() {
+			restDays = append(restDays, fmt.Sprintf("%d %s", day.Day(), reason))
+		}
+		result += cell + mark + count
+
+		if day.Weekday() == time.Sunday {
+			result += "\n"
+		}
+	}
+	result += "\n\nNumbers after a day count its pomodoros, rest days are dimmed.\n"
+	if len(restDays) > 0 {
+		result += fmt.Sprintf("Days off: %s\n", strings.Join(restDays, ", "))
+	}
+
+	labels := []string{}
+	for label := range legend {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		result += fmt.Sprintf("%s = %s: %s\n", labelMark(label), label, strings.Join(legend[label], ", "))
+	}
+
+	return result
+}
+
+// labelMark is the first letter of a day label, marking its days in the
+// calendar.
+func labelMark(label string) string {
+	for _, r := range label {
+		return string(r)
+	}
+	return " "
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestCalendarMarksMultibyteLabels(t *testing.T) {
+	month := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+	days := map[string]dayEntry{"2024-05-06": {Labels: []string{"éxito", "sick"}}}
+	view := calendarView(newSessionIndex(nil), days, newRestCalendar(config{}, nil), month)
+
+	if !utf8.ValidString(view) {
+		t.Fatalf("calendar is not valid UTF-8:\n%s", view)
+	}
+	if !strings.Contains(view, "  6é") {
+		t.Errorf("calendar does not mark the 6th with é:\n%s", view)
+	}
+	if !strings.Contains(view, "é = éxito: 6\n") {
+		t.Errorf("legend lacks the éxito label:\n%s", view)
+	}
+}
+
+func TestRunReportRejectsReversedRange(t *testing.T) {
+	chdirTemp(t)
+	err := runReport([]string{"-from", "2024-05-10", "-to", "2024-05-01"})
+	if err == nil {
+		t.Error("runReport() with -from after -to succeeded")
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const commandsUsage = `Usage: pomodoro [-log-level level] [-debug] [command]
+
+Without a command the interactive timer starts.
+
+Commands:
+  daemon            run the timer in the background without a UI
+  list [YYYY-MM-DD] list completed sessions of today or a day (-template)
+  status            show the running timer (-template)
+  ctl <command>     control a running timer: start [work|break] [minutes],
+                    stop, pause, resume, status
+  install-service   write systemd user units for the daemon
+  standup           print yesterday's focus, today's plan and blockers
+                    (-copy to copy it to the clipboard)
+  plan <task>       plan a task for today (-blocker to note a blocker)
+  day [note]        show or annotate a day (-date, -label sick|travel|...)
+  report            focus per day (-from, -to, -exclude labels, -by-label)
+  summary           summarize a day or month (-date, -month, -format
+                    plain|markdown, -copy to copy it to the clipboard)
+  export            export sessions: -format influx [-o file] [-post],
+                    -format parquet [-o file] [-partition]
+  holidays          list holidays, or import them with: holidays import <file.ics>
+`
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("pomodoro "+name, flag.ExitOnError)
+}
+
+func runCommand(args []string) error {
+	switch args[0] {
+	case "daemon":
+		return runDaemon()
+	case "ctl":
+		return runCtl(args[1:])
+	case "list":
+		return runList(args[1:])
+	case "status":
+		return runStatus(args[1:])
+	case "install-service":
+		return installService(args[1:])
+	case "standup":
+		return runStandup(args[1:])
+	case "plan":
+		return runPlan(args[1:])
+	case "day":
+		return runDay(args[1:])
+	case "report":
+		return runReport(args[1:])
+	case "summary":
+		return runSummary(args[1:])
+	case "export":
+		return runExport(args[1:])
+	case "holidays":
+		return runHolidays(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(commandsUsage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, commandsUsage)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// runProgram runs the model with the D-Bus service and control socket
+// attached. Neither is required for the timer to work.
+func runProgram(m model, opts ...tea.ProgramOption) error {
+	m.service = &timerService{}
+
+	listener, err := listenControl()
+	if err != nil {
+		m.toast(slog.LevelWarn, fmt.Sprintf("No control socket: %v", err))
+	}
+
+	program := tea.NewProgram(m, opts...)
+	control := newController(program)
+
+	if !m.config.DBus.Disabled {
+		_ = m.service.start(control)
+		defer m.service.close()
+	}
+
+	if listener != nil {
+		defer listener.Close()
+		go serveControl(listener, control)
+	}
+
+	if interval := watchdogInterval(); interval > 0 {
+		go runWatchdog(control, interval)
+	}
+
+	// READY=1 is sent from the event loop, see readyMsg.
+	defer sdNotify("STOPPING=1")
+
+	final, err := program.Run()
+	if err != nil {
+		return err
+	}
+
+	// Write anything the background saves did not get to before quitting.
+	return final.(model).flush()
+}
+
+func runDaemon() error {
+	m := initialModel()
+	m.headless = true
+	return runProgram(m, tea.WithInput(nil), tea.WithoutRenderer())
+}
+
+func runCtl(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing command, e.g. pomodoro ctl status")
+	}
+
+	reply, err := sendControlLine(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(reply)
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+const configFile = "config.json"
+
+// loadConfig reads config.json. Without one every setting has its default;
+// one that cannot be read or parsed is an error rather than silently
+// turning every configured feature off.
+func loadConfig() (config, error) {
+	data, err := os.ReadFile(configFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config{}, nil
+	}
+	if err != nil {
+		return config{}, fmt.Errorf("Error reading %s: %v", configFile, err.Error())
+	}
+
+	cfg := config{}
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return config{}, fmt.Errorf("Error parsing %s: %v", configFile, err.Error())
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	chdirTemp(t)
+
+	if cfg, err := loadConfig(); err != nil || cfg.DailyGoal != 0 {
+		t.Errorf("loadConfig() without a file = %+v, %v, want the defaults", cfg, err)
+	}
+
+	os.WriteFile(configFile, []byte(`{"daily_goal": 8, "mpris": {"enabled": true}}`), 0644)
+	if cfg, err := loadConfig(); err != nil || cfg.DailyGoal != 8 || !cfg.MPRIS.Enabled {
+		t.Errorf("loadConfig() = %+v, %v, want the daily goal and MPRIS", cfg, err)
+	}
+
+	os.WriteFile(configFile, []byte(`{"daily_goal": 8,}`), 0644)
+	if _, err := loadConfig(); err == nil {
+		t.Error("loadConfig() with a typo succeeded")
+	}
+}
+
+func TestBrokenConfigShowsToast(t *testing.T) {
+	chdirTemp(t)
+	os.WriteFile(configFile, []byte(`{"daily_goal": 8,}`), 0644)
+
+	m := initialModel()
+	if len(m.toasts) != 1 || !strings.Contains(m.toasts[0].text, "Error parsing config.json") {
+		t.Errorf("toasts = %+v, want the config error", m.toasts)
+	}
+
+	if err := runSummary(nil); err == nil {
+		t.Error("runSummary() with a broken config succeeded")
+	}
+}
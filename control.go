@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	controlTimeout = 5 * time.Second
+	controlQueue   = 16
+)
+
+// controller hands control requests to the program from one goroutine. If
+// the event loop hangs, requests queue up and are then refused, instead of
+// each leaving a goroutine blocked in program.Send.
+type controller struct {
+	requests chan controlMsg
+}
+
+func newController(program *tea.Program) controller {
+	c := controller{requests: make(chan controlMsg, controlQueue)}
+	go func() {
+		for msg := range c.requests {
+			program.Send(msg)
+		}
+	}()
+	return c
+}
+
+// send delivers a control request to the program and waits for the model
+// to handle it.
+func (c controller) send(msg controlMsg) (timerStatus, error) {
+	msg.reply = make(chan controlReply, 1)
+	select {
+	case c.requests <- msg:
+	default:
+		return timerStatus{}, errors.New("timer is busy")
+	}
+
+	select {
+	case reply := <-msg.reply:
+		return reply.status, reply.err
+	case <-time.After(controlTimeout):
+		return timerStatus{}, errors.New("timer did not respond")
+	}
+}
+
+func controlSocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("pomodoro-%d.sock", os.Getuid()))
+	}
+	return filepath.Join(runtimeDir, "pomodoro.sock")
+}
+
+// listenControl opens the control socket unless another timer already
+// serves it. A stale socket file left by a crashed process is replaced.
+//
+// Whether the socket is in use is told by a lock file next to it rather
+// than by connecting, which would start the daemon when pomodoro.socket is
+// enabled.
+func listenControl() (net.Listener, error) {
+	path := controlSocketPath()
+	lock := path + ".lock"
+
+	listener, err := activatedListener()
+	if listener != nil {
+		// Claimed so other timers leave the socket alone.
+		if claimControl(path, lock) != nil {
+			return listener, nil
+		}
+		return controlListener{Listener: listener, lock: lock}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = claimControl(path, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); err == nil && socketUnitActive() {
+		os.Remove(lock)
+		return nil, fmt.Errorf("pomodoro.socket is active, so %s belongs to the daemon; use pomodoro ctl", path)
+	}
+	os.Remove(path)
+
+	listener, err = net.Listen("unix", path)
+	if err != nil {
+		os.Remove(lock)
+		return nil, err
+	}
+	return controlListener{Listener: listener, lock: lock}, nil
+}
+
+// controlListener gives up the lock on the control socket when closed.
+type controlListener struct {
+	net.Listener
+	lock string
+}
+
+func (l controlListener) Close() error {
+	os.Remove(l.lock)
+	return l.Listener.Close()
+}
+
+// claimControl creates the lock file with this process's id. A lock left by
+// a process that is gone is taken over.
+func claimControl(path string, lock string) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = fmt.Fprint(file, os.Getpid())
+			file.Close()
+			return err
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+
+		if pid := lockOwner(lock); pid != 0 {
+			return fmt.Errorf("another timer (pid %d) is already listening on %s", pid, path)
+		}
+		os.Remove(lock)
+	}
+	return errors.New("could not lock " + path)
+}
+
+// lockOwner returns the id of the running process holding lock, or 0.
+func lockOwner(lock string) int {
+	data, err := os.ReadFile(lock)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil || process.Signal(syscall.Signal(0)) != nil {
+		return 0
+	}
+	return pid
+}
+
+// serveControl answers one line based command per connection, e.g.
+// "start work 25", "pause" or "status".
+func serveControl(listener net.Listener, control controller) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(controlTimeout * 2))
+
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil && line == "" {
+				return
+			}
+
+			status, err := control.send(parseControlLine(line))
+			if err != nil {
+				fmt.Fprintf(conn, "error: %v\n", err)
+				return
+			}
+			fmt.Fprintln(conn, formatStatus(status))
+		}()
+	}
+}
+
+func parseControlLine(line string) controlMsg {
+	fields := strings.Fields(line)
+	msg := controlMsg{}
+	if len(fields) > 0 {
+		msg.action = fields[0]
+	}
+	if len(fields) > 1 {
+		msg.sessionType = fields[1]
+	}
+	if len(fields) > 2 {
+		msg.minutes, _ = strconv.Atoi(fields[2])
+	}
+	return msg
+}
+
+func formatStatus(status timerStatus) string {
+	if status.State == "idle" {
+		return status.State
+	}
+	return fmt.Sprintf("%s %s %s", status.State, status.SessionType, status.Remaining)
+}
+
+// parseStatus reads a status line written by formatStatus.
+func parseStatus(line string) timerStatus {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return timerStatus{State: "idle"}
+	}
+
+	remaining, _ := time.ParseDuration(fields[2])
+	return timerStatus{State: fields[0], SessionType: fields[1], Remaining: remaining}
+}
+
+// sendControlLine sends a command to a running timer over the control socket.
+func sendControlLine(line string) (string, error) {
+	conn, err := net.DialTimeout("unix", controlSocketPath(), controlTimeout)
+	if err != nil {
+		return "", fmt.Errorf("no timer is running: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = fmt.Fprintln(conn, line)
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "error: ") {
+		return "", errors.New(strings.TrimPrefix(reply, "error: "))
+	}
+	return reply, nil
+}
+
+func (m *model) control(msg controlMsg) (tea.Cmd, error) {
+	switch msg.action {
+	case "start":
+		if m.inSession {
+			return nil, errors.New("a session is already running")
+		}
+
+		sessionType, numOfMinutes := workSession, defaultWorkMinutes
+		switch strings.ToLower(msg.sessionType) {
+		case "", "work":
+			m.project = ""
+			m.tags = nil
+		case "break":
+			sessionType, numOfMinutes = breakSession, defaultBreakMinutes
+		default:
+			return nil, fmt.Errorf("unknown session type %q", msg.sessionType)
+		}
+		if msg.minutes > 0 {
+			numOfMinutes = msg.minutes
+		}
+
+		m.preset = ""
+		m.showSession = false
+		return m.startSession(sessionType, numOfMinutes), nil
+	case "stop":
+		if !m.inSession {
+			return nil, errors.New("no session is running")
+		}
+		if m.closing {
+			return nil, errors.New("the session is already finishing")
+		}
+		return m.stopSession(), nil
+	case "pause":
+		if !m.inSession || m.opening || m.closing || m.paused {
+			return nil, errors.New("no running session to pause")
+		}
+		return m.pauseSession(), nil
+	case "resume":
+		if !m.paused {
+			return nil, errors.New("the session is not paused")
+		}
+		return m.resumeSession(), nil
+	case "status", "ping":
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown action %q", msg.action)
+}
+
+func (m model) status() timerStatus {
+	if !m.inSession {
+		return timerStatus{State: "idle"}
+	}
+
+	state := "running"
+	switch {
+	case m.opening:
+		state = "starting"
+	case m.closing:
+		state = "finishing"
+	case m.paused:
+		state = "paused"
+	}
+
+	remaining := m.remainingTime
+	if m.ticking && !m.agenda.active() {
+		remaining = m.untilDeadline()
+	}
+	remaining = min(remaining, m.timerDuration)
+	remaining = max(remaining, 0)
+
+	return timerStatus{State: state, Remaining: remaining, SessionType: m.sessionType}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeSystemctl puts a systemctl on PATH that exits with code.
+func fakeSystemctl(t *testing.T, code int) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexit " + strconv.Itoa(code) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "systemctl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestListenControlLock(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	fakeSystemctl(t, 3)
+
+	first, err := listenControl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listenControl(); err == nil || !strings.Contains(err.Error(), "another timer") {
+		t.Errorf("second listenControl() = %v, want the socket in use", err)
+	}
+
+	first.Close()
+	if _, err := os.Stat(controlSocketPath() + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock left behind after Close(): %v", err)
+	}
+
+	again, err := listenControl()
+	if err != nil {
+		t.Fatalf("listenControl() after Close() = %v", err)
+	}
+	again.Close()
+}
+
+func TestListenControlTakesOverStaleLock(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	fakeSystemctl(t, 3)
+
+	// A process that has exited.
+	cmd := exec.Command("/bin/sh", "-c", "exit 0")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(controlSocketPath()+".lock", []byte(strconv.Itoa(cmd.Process.Pid)), 0600)
+	os.WriteFile(controlSocketPath(), nil, 0600)
+
+	listener, err := listenControl()
+	if err != nil {
+		t.Fatalf("listenControl() with a stale lock = %v", err)
+	}
+	listener.Close()
+}
+
+func TestListenControlLeavesSocketUnitAlone(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	fakeSystemctl(t, 0)
+
+	os.WriteFile(controlSocketPath(), nil, 0600)
+	if _, err := listenControl(); err == nil || !strings.Contains(err.Error(), "pomodoro.socket") {
+		t.Errorf("listenControl() with pomodoro.socket active = %v, want the conflict", err)
+	}
+	if _, err := os.Stat(controlSocketPath()); err != nil {
+		t.Errorf("socket of the unit was removed: %v", err)
+	}
+	if _, err := os.Stat(controlSocketPath() + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock left behind: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const daysFile = "days.json"
+
+// dayEntry annotates a whole day with labels such as "sick" or "on-call"
+// and journal notes.
+type dayEntry struct {
+	Labels []string `json:"labels,omitempty"`
+	Notes  []string `json:"notes,omitempty"`
+}
+
+func loadDays() (map[string]dayEntry, error) {
+	days := map[string]dayEntry{}
+	err := readJSON(daysFile, &days)
+	if err != nil {
+		return map[string]dayEntry{}, err
+	}
+
+	return days, nil
+}
+
+func saveDays(days map[string]dayEntry) error {
+	data, err := json.MarshalIndent(days, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error marshal data: %v", err.Error())
+	}
+
+	err = writeFile(daysFile, data)
+	if err != nil {
+		return fmt.Errorf("Error writing to file: %v", err.Error())
+	}
+
+	return nil
+}
+
+// toggleDayLabel adds label to the day or removes it when already set.
+func toggleDayLabel(days map[string]dayEntry, date time.Time, label string) {
+	key := date.Format(time.DateOnly)
+	entry := days[key]
+
+	if i := slices.Index(entry.Labels, label); i >= 0 {
+		entry.Labels = slices.Delete(entry.Labels, i, i+1)
+	} else {
+		entry.Labels = append(entry.Labels, label)
+		sort.Strings(entry.Labels)
+	}
+	setDay(days, key, entry)
+}
+
+func addDayNote(days map[string]dayEntry, date time.Time, note string) {
+	key := date.Format(time.DateOnly)
+	entry := days[key]
+	entry.Notes = append(entry.Notes, note)
+	setDay(days, key, entry)
+}
+
+func setDay(days map[string]dayEntry, key string, entry dayEntry) {
+	if len(entry.Labels) == 0 && len(entry.Notes) == 0 {
+		delete(days, key)
+		return
+	}
+	days[key] = entry
+}
+
+func (e dayEntry) hasLabel(labels []string) bool {
+	for _, label := range e.Labels {
+		if slices.Contains(labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// dayHeader lists the labels and notes of a day for the history view.
+func dayHeader(entry dayEntry) string {
+	header := ""
+	if len(entry.Labels) > 0 {
+		header += fmt.Sprintf("Labels: %s\n", strings.Join(entry.Labels, ", "))
+	}
+	for _, note := range entry.Notes {
+		header += fmt.Sprintf("Journal: %s\n", note)
+	}
+	return header
+}
+
+// parseDayCommand reads "<argument> [YYYY-MM-DD]" as used by the label
+// command, defaulting to today.
+func parseDayCommand(args string) (string, time.Time, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return "", time.Time{}, fmt.Errorf("Invalid command")
+	}
+
+	date := time.Now()
+	if len(fields) > 1 {
+		parsed, err := time.ParseInLocation(time.DateOnly, fields[len(fields)-1], time.Local)
+		if err == nil {
+			date = parsed
+			fields = fields[:len(fields)-1]
+		}
+	}
+	return strings.Join(fields, " "), date, nil
+}
+
+func runDay(args []string) error {
+	flags := newFlagSet("day")
+	date := flags.String("date", "", "day to annotate as YYYY-MM-DD (default today)")
+	label := flags.String("label", "", "add or remove a label such as sick, travel, on-call or deep-work")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	day := time.Now()
+	if *date != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, *date, time.Local)
+		if err != nil {
+			return fmt.Errorf("Invalid date format")
+		}
+		day = parsed
+	}
+
+	days, err := loadDays()
+	if err != nil {
+		return err
+	}
+
+	note := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	if *label == "" && note == "" {
+		fmt.Printf("%s\n%s", day.Format(time.DateOnly), dayHeader(days[day.Format(time.DateOnly)]))
+		return nil
+	}
+
+	if *label != "" {
+		toggleDayLabel(days, day, *label)
+	}
+	if note != "" {
+		addDayNote(days, day, note)
+	}
+	return saveDays(days)
+}
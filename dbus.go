@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const (
+	timerBusName = "org.pomodoro.Timer"
+	timerPath    = "/org/pomodoro/Timer"
+	timerIface   = "org.pomodoro.Timer"
+)
+
+// timerService exports the timer on the session bus. Its exported methods
+// are the D-Bus methods of the org.pomodoro.Timer interface.
+type timerService struct {
+	control controller
+	conn    *dbus.Conn
+	props   *prop.Properties
+	mu      sync.Mutex
+	last    timerStatus
+}
+
+func (s *timerService) start(control controller) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+
+	s.control = control
+	s.last = timerStatus{State: "idle"}
+
+	err = conn.Export(s, timerPath, timerIface)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	props, err := prop.Export(conn, timerPath, prop.Map{
+		timerIface: {
+			"State":       {Value: s.last.State, Emit: prop.EmitTrue},
+			"Remaining":   {Value: int64(0), Emit: prop.EmitTrue},
+			"SessionType": {Value: "", Emit: prop.EmitTrue},
+		},
+	})
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	node := &introspect.Node{
+		Name: timerPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{
+				Name:       timerIface,
+				Methods:    introspect.Methods(s),
+				Properties: props.Introspection(timerIface),
+			},
+		},
+	}
+	err = conn.Export(introspect.NewIntrospectable(node), timerPath, "org.freedesktop.DBus.Introspectable")
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	reply, err := conn.RequestName(timerBusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return errors.New(timerBusName + " is already owned by another process")
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.props = props
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *timerService) close() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// publish updates the exported properties, emitting PropertiesChanged for
+// each value that differs from the last published status.
+func (s *timerService) publish(status timerStatus) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return
+	}
+
+	if status.State != s.last.State {
+		s.props.SetMust(timerIface, "State", status.State)
+	}
+	if status.seconds() != s.last.seconds() {
+		s.props.SetMust(timerIface, "Remaining", status.seconds())
+	}
+	if status.SessionType != s.last.SessionType {
+		s.props.SetMust(timerIface, "SessionType", status.SessionType)
+	}
+	s.last = status
+}
+
+func (s *timerService) call(msg controlMsg) *dbus.Error {
+	_, err := s.control.send(msg)
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// Start begins a "work" or "break" session. Zero minutes uses the default
+// length for the session type.
+func (s *timerService) Start(sessionType string, minutes int32) *dbus.Error {
+	return s.call(controlMsg{action: "start", sessionType: sessionType, minutes: int(minutes)})
+}
+
+func (s *timerService) Stop() *dbus.Error {
+	return s.call(controlMsg{action: "stop"})
+}
+
+func (s *timerService) Pause() *dbus.Error {
+	return s.call(controlMsg{action: "pause"})
+}
+
+func (s *timerService) Resume() *dbus.Error {
+	return s.call(controlMsg{action: "resume"})
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// startTimerService runs a headless timer exported on the private bus.
+func startTimerService(t *testing.T) {
+	t.Helper()
+	privateBus(t)
+	chdirTemp(t)
+
+	m := initialModel()
+	m.headless = true
+	m.service = &timerService{}
+	program := tea.NewProgram(m, tea.WithInput(nil), tea.WithoutRenderer())
+	if err := m.service.start(newController(program)); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		program.Run()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		program.Quit()
+		<-done
+		m.service.close()
+	})
+}
+
+// watchTimer returns the timer object and its PropertiesChanged signals.
+func watchTimer(t *testing.T) (dbus.BusObject, chan *dbus.Signal) {
+	t.Helper()
+	conn, err := dbus.Connect(testBus.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	err = conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(timerPath),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signals := make(chan *dbus.Signal, 64)
+	conn.Signal(signals)
+
+	return conn.Object(timerBusName, timerPath), signals
+}
+
+// waitForProperty reads signals until name changes to want.
+func waitForProperty(t *testing.T, signals chan *dbus.Signal, name string, want any) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case signal := <-signals:
+			if len(signal.Body) < 2 || signal.Body[0] != timerIface {
+				continue
+			}
+			changed, _ := signal.Body[1].(map[string]dbus.Variant)
+			if value, ok := changed[name]; ok && value.Value() == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no PropertiesChanged signal with %s = %v", name, want)
+		}
+	}
+}
+
+func TestTimerServiceMethods(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the 3 second start countdown")
+	}
+	startTimerService(t)
+	timer, signals := watchTimer(t)
+
+	if err := timer.Call(timerIface+".Start", 0, "work", int32(1)).Err; err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	waitForProperty(t, signals, "State", "starting")
+	waitForProperty(t, signals, "SessionType", workSession)
+
+	if err := timer.Call(timerIface+".Start", 0, "work", int32(1)).Err; err == nil {
+		t.Error("Start() while a session runs succeeded")
+	}
+	if err := timer.Call(timerIface+".Pause", 0).Err; err == nil {
+		t.Error("Pause() during the start countdown succeeded")
+	}
+
+	waitForProperty(t, signals, "State", "running")
+	waitForProperty(t, signals, "Remaining", int64(59))
+
+	if err := timer.Call(timerIface+".Pause", 0).Err; err != nil {
+		t.Fatalf("Pause() = %v", err)
+	}
+	waitForProperty(t, signals, "State", "paused")
+
+	state, err := timer.GetProperty(timerIface + ".State")
+	if err != nil || state.Value() != "paused" {
+		t.Errorf("State = %v, %v, want paused", state, err)
+	}
+
+	if err := timer.Call(timerIface+".Resume", 0).Err; err != nil {
+		t.Fatalf("Resume() = %v", err)
+	}
+	waitForProperty(t, signals, "State", "running")
+
+	if err := timer.Call(timerIface+".Stop", 0).Err; err != nil {
+		t.Fatalf("Stop() = %v", err)
+	}
+	waitForProperty(t, signals, "State", "idle")
+
+	if err := timer.Call(timerIface+".Stop", 0).Err; err == nil {
+		t.Error("Stop() without a session succeeded")
+	}
+}
+
+func TestTimerServiceRejectsUnknownType(t *testing.T) {
+	startTimerService(t)
+	timer, _ := watchTimer(t)
+
+	if err := timer.Call(timerIface+".Start", 0, "nap", int32(0)).Err; err == nil {
+		t.Error("Start(nap) succeeded")
+	}
+
+	state, err := timer.GetProperty(timerIface + ".State")
+	if err != nil || state.Value() != "idle" {
+		t.Errorf("State = %v, %v, want idle", state, err)
+	}
+}
+
+func TestTimerServicePublishesWholeSeconds(t *testing.T) {
+	privateBus(t)
+	service := &timerService{}
+	if err := service.start(controller{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(service.close)
+	_, signals := watchTimer(t)
+
+	for _, remaining := range []time.Duration{
+		59900 * time.Millisecond,
+		59500 * time.Millisecond,
+		59100 * time.Millisecond,
+		58900 * time.Millisecond,
+	} {
+		service.publish(timerStatus{State: "running", Remaining: remaining, SessionType: workSession})
+	}
+	service.publish(timerStatus{State: "idle"})
+
+	got := []int64{}
+	timeout := time.After(5 * time.Second)
+	for len(got) == 0 || got[len(got)-1] != 0 {
+		select {
+		case signal := <-signals:
+			changed, _ := signal.Body[1].(map[string]dbus.Variant)
+			if value, ok := changed["Remaining"]; ok {
+				got = append(got, value.Value().(int64))
+			}
+		case <-timeout:
+			t.Fatalf("Remaining changes = %v, never reset to 0", got)
+		}
+	}
+	want := []int64{59, 58, 0}
+	if !slices.Equal(got, want) {
+		t.Errorf("Remaining changes = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type eventKind string
+
+const (
+	eventStart    eventKind = "start"
+	eventComplete eventKind = "complete"
+	eventAbandon  eventKind = "abandon"
+	eventPause    eventKind = "pause"
+	eventResume   eventKind = "resume"
+	eventMark     eventKind = "mark"
+)
+
+type lifecycleEvent struct {
+	Kind        eventKind
+	SessionType string
+	Preset      string
+	Project     string
+	Tags        []string
+	Mark        string
+	Duration    time.Duration
+	Time        time.Time
+}
+
+// integrationErrMsg reports a failure from an integration running in the
+// background so it can be shown in the UI.
+type integrationErrMsg struct{ err error }
+
+// name returns the event key used in the config, e.g. "work_start".
+func (e lifecycleEvent) name() string {
+	return strings.ToLower(e.SessionType) + "_" + string(e.Kind)
+}
+
+// emit notifies every configured integration about a session transition.
+func (m model) emit(kind eventKind) tea.Cmd {
+	return m.dispatch(m.event(kind))
+}
+
+func (m model) event(kind eventKind) lifecycleEvent {
+	return lifecycleEvent{
+		Kind:        kind,
+		SessionType: m.sessionType,
+		Preset:      m.preset,
+		Project:     m.project,
+		Tags:        m.tags,
+		Duration:    m.timerDuration,
+		Time:        time.Now(),
+	}
+}
+
+func (m model) dispatch(event lifecycleEvent) tea.Cmd {
+	logger.Info("session event", "event", event.name(), "project", event.Project,
+		"preset", event.Preset, "mark", event.Mark, "duration", event.Duration)
+	return tea.Batch(
+		mprisCmd(m.config.MPRIS, event, m.config.Presets[m.preset].Playlist),
+		journalCmd(event),
+		syslogCmd(m.config.Syslog, event),
+		statsdCmd(m.config.Statsd, event),
+		ttsCmd(m.config.TTS, event, m.muted),
+		alertCmd(m, event),
+		hookCmd(m.config.Hooks, event),
+	)
+}
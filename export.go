@@ -0,0 +1,35 @@
+package main
+
+import "fmt"
+
+func runExport(args []string) error {
+	flags := newFlagSet("export")
+	format := flags.String("format", "", "export format: influx or parquet")
+	output := flags.String("o", "", "file to write to (default stdout for influx, sessions.parquet for parquet)")
+	post := flags.Bool("post", false, "post sessions since the last post to influx.url (influx only)")
+	partition := flags.Bool("partition", false, "write a year=YYYY/month=MM tree under -o, sessions by default (parquet only)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := loadSessions()
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "influx":
+		return exportInflux(cfg.Influx, sessions, *output, *post)
+	case "parquet":
+		return exportParquet(sessions, *output, *partition)
+	case "":
+		return fmt.Errorf("Choose a format with -format influx or -format parquet")
+	default:
+		return fmt.Errorf("Unknown export format %q", *format)
+	}
+}
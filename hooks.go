@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxHookOutput caps how much of a failed hook's output is shown.
+const maxHookOutput = 200
+
+// hookCmd runs the command configured for the event, e.g. "work_complete":
+// ["sh", "-c", "git commit -am wip"], with the event in its environment.
+// A failure is reported with the end of the hook's output.
+func hookCmd(hooks map[string][]string, event lifecycleEvent) tea.Cmd {
+	command := hooks[event.name()]
+	if len(command) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Env = append(os.Environ(), hookEnv(event)...)
+
+		var output []byte
+		err := traced("hook", func() error {
+			var err error
+			output, err = cmd.CombinedOutput()
+			return err
+		})
+		if err != nil {
+			text := strings.TrimSpace(string(output))
+			if len(text) > maxHookOutput {
+				text = "…" + text[len(text)-maxHookOutput:]
+			}
+			if text != "" {
+				err = fmt.Errorf("%v: %s", err, text)
+			}
+			return integrationErrMsg{fmt.Errorf("Error running %s hook: %v", event.name(), err)}
+		}
+		return nil
+	}
+}
+
+func hookEnv(event lifecycleEvent) []string {
+	return []string{
+		"POMODORO_EVENT=" + event.name(),
+		"POMODORO_TYPE=" + strings.ToLower(event.SessionType),
+		"POMODORO_PRESET=" + event.Preset,
+		"POMODORO_PROJECT=" + event.Project,
+		"POMODORO_TAGS=" + strings.Join(event.Tags, ","),
+		"POMODORO_MARK=" + event.Mark,
+		"POMODORO_DURATION=" + strconv.Itoa(int(event.Duration.Seconds())),
+	}
+}
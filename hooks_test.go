@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookCmd(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	hooks := map[string][]string{
+		"work_complete": {"sh", "-c", `echo "$POMODORO_EVENT $POMODORO_PROJECT $POMODORO_TAGS $POMODORO_DURATION" > ` + out},
+		"break_start":   {"sh", "-c", "echo starting; echo no network >&2; exit 3"},
+	}
+	event := lifecycleEvent{Kind: eventComplete, SessionType: workSession, Project: "api",
+		Tags: []string{"review", "pair"}, Duration: 25 * time.Minute}
+
+	if msg := hookCmd(hooks, event)(); msg != nil {
+		t.Fatalf("hook = %v, want nothing", msg)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "work_complete api review,pair 1500"; got != want {
+		t.Errorf("hook saw %q, want %q", got, want)
+	}
+
+	msg, ok := hookCmd(hooks, lifecycleEvent{Kind: eventStart, SessionType: breakSession})().(integrationErrMsg)
+	if !ok {
+		t.Fatal("failing hook reports no error")
+	}
+	if !strings.Contains(msg.err.Error(), "exit status 3: starting\nno network") {
+		t.Errorf("failing hook = %v, want its exit status and output", msg.err)
+	}
+
+	if cmd := hookCmd(hooks, lifecycleEvent{Kind: eventPause, SessionType: workSession}); cmd != nil {
+		t.Error("hookCmd() runs without a hook for the event")
+	}
+}
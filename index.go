@@ -0,0 +1,169 @@
+package main
+
+import (
+	"slices"
+	"time"
+)
+
+// aggregate sums up a group of completed sessions.
+type aggregate struct {
+	Pomodoros int
+	Focus     time.Duration
+	Standing  time.Duration
+	Breaks    time.Duration
+	Outcomes  outcomeCounts
+}
+
+// outcomeCounts counts reviewed intents by how well they were achieved.
+type outcomeCounts struct {
+	Yes    int
+	Partly int
+	No     int
+}
+
+// add counts s in the totals, or takes it out again with a sign of -1.
+func (a *aggregate) add(s session, sign int) {
+	if !s.isWork() {
+		a.Breaks += time.Duration(sign) * s.Duration
+		return
+	}
+
+	a.Pomodoros += sign
+	a.Focus += time.Duration(sign) * s.Duration
+	if s.Posture == standing {
+		a.Standing += time.Duration(sign) * s.Duration
+	}
+	if s.Intent != "" {
+		a.Outcomes.add(s.Outcome, sign)
+	}
+}
+
+func (c *outcomeCounts) add(outcome string, sign int) {
+	switch outcome {
+	case "yes":
+		c.Yes += sign
+	case "partly":
+		c.Partly += sign
+	case "no":
+		c.No += sign
+	}
+}
+
+// sessionIndex groups sessions by the local day they ended on and keeps
+// running totals per day, week and project so views don't rescan the
+// whole history. Editing a session is a remove followed by an add.
+type sessionIndex struct {
+	byDay    map[string][]session
+	days     map[string]*aggregate
+	weeks    map[string]*aggregate
+	projects map[string]*aggregate
+}
+
+func newSessionIndex(sessions []session) sessionIndex {
+	index := sessionIndex{
+		byDay:    map[string][]session{},
+		days:     map[string]*aggregate{},
+		weeks:    map[string]*aggregate{},
+		projects: map[string]*aggregate{},
+	}
+	for _, s := range sessions {
+		index.add(s)
+	}
+	return index
+}
+
+// dayKey is the local day a session counts towards.
+func dayKey(s session) string {
+	return s.completedAt().Local().Format(time.DateOnly)
+}
+
+func (x sessionIndex) add(s session) {
+	key := dayKey(s)
+	x.byDay[key] = append(x.byDay[key], s)
+	x.count(s, 1)
+}
+
+// remove takes out the session that started at the same time as s.
+func (x sessionIndex) remove(s session) {
+	key := dayKey(s)
+	i := slices.IndexFunc(x.byDay[key], func(other session) bool {
+		return other.StartTime.Equal(s.StartTime) && other.Type == s.Type
+	})
+	if i < 0 {
+		return
+	}
+
+	x.byDay[key] = slices.Delete(x.byDay[key], i, i+1)
+	if len(x.byDay[key]) == 0 {
+		delete(x.byDay, key)
+	}
+	x.count(s, -1)
+}
+
+// update replaces old with s.
+func (x sessionIndex) update(old session, s session) {
+	x.remove(old)
+	x.add(s)
+}
+
+func (x sessionIndex) count(s session, sign int) {
+	end := s.completedAt().Local()
+	bump(x.days, end.Format(time.DateOnly), s, sign)
+	bump(x.weeks, weekStart(end).Format(time.DateOnly), s, sign)
+	bump(x.projects, s.Project, s, sign)
+}
+
+func bump(aggregates map[string]*aggregate, key string, s session, sign int) {
+	a, ok := aggregates[key]
+	if !ok {
+		a = &aggregate{}
+		aggregates[key] = a
+	}
+	a.add(s, sign)
+	if *a == (aggregate{}) {
+		delete(aggregates, key)
+	}
+}
+
+// day returns the sessions completed on date.
+func (x sessionIndex) day(date time.Time) []session {
+	return x.byDay[date.Format(time.DateOnly)]
+}
+
+// totals returns the totals of the sessions completed on date.
+func (x sessionIndex) totals(date time.Time) aggregate {
+	return lookup(x.days, date.Format(time.DateOnly))
+}
+
+// week returns the totals of the sessions completed in the week starting
+// on the Monday start.
+func (x sessionIndex) week(start time.Time) aggregate {
+	return lookup(x.weeks, start.Format(time.DateOnly))
+}
+
+// project returns the totals of the project's sessions.
+func (x sessionIndex) project(name string) aggregate {
+	return lookup(x.projects, name)
+}
+
+// projectNames lists the projects with sessions, sorted by name.
+func (x sessionIndex) projectNames() []string {
+	names := []string{}
+	for name := range x.projects {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func lookup(aggregates map[string]*aggregate, key string) aggregate {
+	if a, ok := aggregates[key]; ok {
+		return *a
+	}
+	return aggregate{}
+}
+
+// pomodoros counts the work sessions completed on date.
+func (x sessionIndex) pomodoros(date time.Time) int {
+	return x.totals(date).Pomodoros
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const benchSessions = 100_000
+
+// generateSessions returns alternating work sessions and breaks back to
+// back, 16 minutes each on average, ending now.
+func generateSessions(n int) []session {
+	sessions := make([]session, 0, n)
+	start := time.Now().Add(-time.Duration(n) * 16 * time.Minute)
+	for i := 0; i < n; i++ {
+		s := session{StartTime: start, Duration: 25 * time.Minute, Project: "api"}
+		switch {
+		case i%2 == 1:
+			s.Type = breakSession
+			s.Duration = 5 * time.Minute
+		case i%3 == 0:
+			s.Intent = "ship it"
+			s.Outcome = []string{"yes", "partly", "no"}[i%9/3]
+		}
+		sessions = append(sessions, s)
+		start = start.Add(s.Duration + time.Minute)
+	}
+	return sessions
+}
+
+func TestSessionIndexTotals(t *testing.T) {
+	day := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	index := newSessionIndex([]session{
+		{StartTime: day, Duration: 25 * time.Minute, Intent: "a", Outcome: "yes", Posture: standing},
+		{StartTime: day.Add(25 * time.Minute), Duration: 5 * time.Minute, Type: breakSession},
+		{StartTime: day.Add(time.Hour), Duration: 50 * time.Minute, Intent: "b", Outcome: "partly"},
+		{StartTime: day.AddDate(0, 0, 1), Duration: 25 * time.Minute},
+	})
+
+	want := aggregate{Pomodoros: 2, Focus: 75 * time.Minute, Standing: 25 * time.Minute,
+		Breaks: 5 * time.Minute, Outcomes: outcomeCounts{Yes: 1, Partly: 1}}
+	if got := index.totals(day); got != want {
+		t.Errorf("totals() = %+v, want %+v", got, want)
+	}
+	if got := len(index.day(day)); got != 3 {
+		t.Errorf("len(day()) = %d, want 3", got)
+	}
+	if got := index.pomodoros(day.AddDate(0, 0, 2)); got != 0 {
+		t.Errorf("pomodoros() on an empty day = %d, want 0", got)
+	}
+}
+
+func TestSessionIndexCountsEndDay(t *testing.T) {
+	lateNight := time.Date(2024, 5, 6, 23, 50, 0, 0, time.Local)
+	index := newSessionIndex([]session{{StartTime: lateNight, Duration: 25 * time.Minute}})
+
+	if got := index.pomodoros(lateNight); got != 0 {
+		t.Errorf("pomodoros(start day) = %d, want 0", got)
+	}
+	if got := index.pomodoros(lateNight.AddDate(0, 0, 1)); got != 1 {
+		t.Errorf("pomodoros(end day) = %d, want 1", got)
+	}
+
+	// Pauses push the end past midnight, as the reports see it.
+	evening := time.Date(2024, 5, 6, 23, 20, 0, 0, time.Local)
+	paused := session{StartTime: evening, EndTime: evening.Add(45 * time.Minute), Duration: 25 * time.Minute}
+	index = newSessionIndex([]session{paused})
+	if got := index.pomodoros(evening.AddDate(0, 0, 1)); got != 1 {
+		t.Errorf("pomodoros(day of the end time) = %d, want 1", got)
+	}
+}
+
+func TestSessionIndexEdits(t *testing.T) {
+	monday := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	a := session{StartTime: monday, Duration: 25 * time.Minute, Project: "api", Intent: "a", Outcome: "yes"}
+	b := session{StartTime: monday.Add(time.Hour), Duration: 25 * time.Minute, Project: "web"}
+	c := session{StartTime: monday.AddDate(0, 0, 7), Duration: 50 * time.Minute, Project: "api"}
+	brk := session{StartTime: monday.Add(25 * time.Minute), Duration: 5 * time.Minute, Type: breakSession}
+
+	index := newSessionIndex(nil)
+	for _, s := range []session{a, b, c, brk} {
+		index.add(s)
+	}
+	if got := index.week(monday).Pomodoros; got != 2 {
+		t.Errorf("week pomodoros = %d, want 2", got)
+	}
+	if got := index.project("api"); got.Pomodoros != 2 || got.Focus != 75*time.Minute {
+		t.Errorf("api totals = %+v, want 2 pomodoros, 75m", got)
+	}
+
+	edited := b
+	edited.Project = "api"
+	edited.Intent, edited.Outcome = "b", "no"
+	index.update(b, edited)
+	index.remove(c)
+
+	// Each step must leave the totals a fresh index would have.
+	assertSameIndex(t, index, newSessionIndex([]session{a, edited, brk}))
+	if got := index.projectNames(); !slices.Equal(got, []string{"", "api"}) {
+		t.Errorf("projectNames() = %q, want the break's empty project and api", got)
+	}
+
+	index.remove(a)
+	index.remove(edited)
+	index.remove(brk)
+	assertSameIndex(t, index, newSessionIndex(nil))
+}
+
+func assertSameIndex(t *testing.T, got sessionIndex, want sessionIndex) {
+	t.Helper()
+	for name, maps := range map[string][2]map[string]*aggregate{
+		"days":     {got.days, want.days},
+		"weeks":    {got.weeks, want.weeks},
+		"projects": {got.projects, want.projects},
+	} {
+		if !reflect.DeepEqual(maps[0], maps[1]) {
+			t.Errorf("%s = %s, want %s", name, formatAggregates(maps[0]), formatAggregates(maps[1]))
+		}
+	}
+	if len(got.byDay) != len(want.byDay) {
+		t.Errorf("sessions on %d days, want %d", len(got.byDay), len(want.byDay))
+	}
+}
+
+func formatAggregates(aggregates map[string]*aggregate) string {
+	result := ""
+	for key, a := range aggregates {
+		result += fmt.Sprintf("%q: %+v ", key, *a)
+	}
+	return result
+}
+
+func BenchmarkNewSessionIndex(b *testing.B) {
+	sessions := generateSessions(benchSessions)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		newSessionIndex(sessions)
+	}
+}
+
+func BenchmarkIndexList(b *testing.B) {
+	index := newSessionIndex(generateSessions(benchSessions))
+	tmpl, err := loadTemplates(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	days := map[string]dayEntry{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		printSessions(index, days, tmpl, false, time.Time{})
+	}
+}
+
+func BenchmarkIndexStats(b *testing.B) {
+	index := newSessionIndex(generateSessions(benchSessions))
+	rest := newRestCalendar(config{}, map[string]string{})
+	days := map[string]dayEntry{}
+	now := time.Now()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		goalLine(index, rest, 8)
+		calendarView(index, days, rest, now)
+		intentReport(index, intentReportWeeks, now)
+	}
+}
+
+func BenchmarkIndexReport(b *testing.B) {
+	index := newSessionIndex(generateSessions(benchSessions))
+	days := map[string]dayEntry{}
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		focusReport(index, days, from, to, nil, false)
+	}
+}
+
+func TestIntentReportByProject(t *testing.T) {
+	monday := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	index := newSessionIndex([]session{
+		{StartTime: monday, Duration: 25 * time.Minute, Project: "api", Intent: "a", Outcome: "yes"},
+		{StartTime: monday.AddDate(0, 0, -30), Duration: 25 * time.Minute, Project: "api", Intent: "b", Outcome: "no"},
+		{StartTime: monday, Duration: 25 * time.Minute, Project: "web"},
+	})
+
+	report := intentReport(index, 1, monday)
+	if !strings.Contains(report, "Overall: 100% achieved") {
+		t.Errorf("report counts intents from before the weeks shown:\n%s", report)
+	}
+	if !strings.Contains(report, "api           50% achieved (1 yes, 0 partly, 1 no)") {
+		t.Errorf("report lacks the all time api line:\n%s", report)
+	}
+	if strings.Contains(report, "web") {
+		t.Errorf("report lists a project without reviewed intents:\n%s", report)
+	}
+}
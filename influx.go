@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	influxMarkFile           = "influx.json"
+	defaultInfluxMeasurement = "pomodoro"
+	influxBatch              = 5000
+	influxTimeout            = 30 * time.Second
+)
+
+type influxConfig struct {
+	URL         string `json:"url"`   // write endpoint, e.g. http://localhost:8086/api/v2/write?org=me&bucket=focus
+	Token       string `json:"token"` // sent as "Authorization: Token <token>"
+	Measurement string `json:"measurement"`
+}
+
+// influxMark remembers when the last session posted to InfluxDB was
+// completed. Sessions are saved as they complete, so every session recorded
+// since has a later completion time, even one started before the mark.
+// A batch can end between sessions completed at the same time, so the mark
+// also keeps the start times of the posted ones completed at LastExported.
+type influxMark struct {
+	LastExported time.Time   `json:"last_exported"`
+	Started      []time.Time `json:"started,omitempty"`
+}
+
+// posted reports whether s was posted before the mark was saved.
+func (mark influxMark) posted(s session) bool {
+	end := s.completedAt()
+	if !end.Equal(mark.LastExported) {
+		return end.Before(mark.LastExported)
+	}
+	return slices.ContainsFunc(mark.Started, s.StartTime.Equal)
+}
+
+// advance moves the mark past the sessions in batch.
+func (mark *influxMark) advance(batch []session) {
+	end := batch[len(batch)-1].completedAt()
+	if !end.Equal(mark.LastExported) {
+		mark.LastExported = end
+		mark.Started = nil
+	}
+	for _, s := range batch {
+		if s.completedAt().Equal(end) {
+			mark.Started = append(mark.Started, s.StartTime)
+		}
+	}
+}
+
+var (
+	influxKeyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+)
+
+// influxLine converts a session to line protocol, e.g.
+//
+//	pomodoro,project=api,status=completed,type=work duration=1500i,interruptions=2i 1715000000000000000
+//
+// Only completed sessions are saved for now, but the status tag keeps room
+// for abandoned ones. Sessions only keep duration and pauses as numbers, so
+// there is no rating field.
+func influxLine(measurement string, s session) string {
+	sessionType := workSession
+	if !s.isWork() {
+		sessionType = breakSession
+	}
+
+	tags := map[string]string{
+		"project": s.Project,
+		"status":  "completed",
+		"type":    strings.ToLower(sessionType),
+		"tags":    strings.Join(s.Tags, ","),
+	}
+	keys := []string{}
+	for key, value := range tags {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	// Sorted tags are what InfluxDB indexes fastest.
+	sort.Strings(keys)
+
+	line := influxMeasurementEscaper.Replace(measurement)
+	for _, key := range keys {
+		line += "," + key + "=" + influxKeyEscaper.Replace(tags[key])
+	}
+	line += fmt.Sprintf(" duration=%di,interruptions=%di %d",
+		int64(s.Duration.Seconds()), s.Pauses, s.StartTime.UnixNano())
+	return line
+}
+
+func influxLines(measurement string, sessions []session) string {
+	if measurement == "" {
+		measurement = defaultInfluxMeasurement
+	}
+
+	var b strings.Builder
+	for _, s := range sessions {
+		b.WriteString(influxLine(measurement, s) + "\n")
+	}
+	return b.String()
+}
+
+func loadInfluxMark() influxMark {
+	data, err := os.ReadFile(influxMarkFile)
+	if err != nil {
+		return influxMark{}
+	}
+
+	mark := influxMark{}
+	err = json.Unmarshal(data, &mark)
+	if err != nil {
+		logger.Error("Error reading export mark", "file", influxMarkFile, "err", err)
+		return influxMark{}
+	}
+	return mark
+}
+
+func saveInfluxMark(mark influxMark) error {
+	data, err := json.MarshalIndent(mark, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error marshal data: %v", err.Error())
+	}
+
+	err = writeFile(influxMarkFile, data)
+	if err != nil {
+		return fmt.Errorf("Error writing to file: %v", err.Error())
+	}
+	return nil
+}
+
+// postInflux writes the sessions completed after the saved mark in
+// batches, moving the mark after each one so an interrupted export resumes
+// where it stopped.
+func postInflux(cfg influxConfig, sessions []session) (int, error) {
+	if cfg.URL == "" {
+		return 0, fmt.Errorf("Set influx.url in %s to post to InfluxDB", configFile)
+	}
+
+	mark := loadInfluxMark()
+	pending := []session{}
+	for _, s := range sessions {
+		if !mark.posted(s) {
+			pending = append(pending, s)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].completedAt().Before(pending[j].completedAt())
+	})
+
+	client := &http.Client{Timeout: influxTimeout}
+	posted := 0
+	for len(pending) > 0 {
+		batch := pending[:min(influxBatch, len(pending))]
+		pending = pending[len(batch):]
+
+		err := traced("influx", func() error {
+			return influxWrite(client, cfg, influxLines(cfg.Measurement, batch))
+		})
+		if err != nil {
+			return posted, err
+		}
+
+		posted += len(batch)
+		mark.advance(batch)
+		err = saveInfluxMark(mark)
+		if err != nil {
+			return posted, err
+		}
+	}
+	return posted, nil
+}
+
+func influxWrite(client *http.Client, cfg influxConfig, lines string) error {
+	request, err := http.NewRequest(http.MethodPost, cfg.URL, bytes.NewBufferString(lines))
+	if err != nil {
+		return fmt.Errorf("Error posting to InfluxDB: %v", err)
+	}
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if cfg.Token != "" {
+		request.Header.Set("Authorization", "Token "+cfg.Token)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("Error posting to InfluxDB: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("Error posting to InfluxDB: %s %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func exportInflux(cfg influxConfig, sessions []session, output string, post bool) error {
+	if post {
+		posted, err := postInflux(cfg, sessions)
+		fmt.Printf("Posted %s to InfluxDB\n", pluralSessions(posted))
+		return err
+	}
+
+	lines := influxLines(cfg.Measurement, sessions)
+	if output == "" || output == "-" {
+		fmt.Print(lines)
+		return nil
+	}
+
+	err := writeFile(output, []byte(lines))
+	if err != nil {
+		return fmt.Errorf("Error writing to file: %v", err.Error())
+	}
+	return nil
+}
+
+func pluralSessions(n int) string {
+	if n == 1 {
+		return "1 session"
+	}
+	return strconv.Itoa(n) + " sessions"
+}
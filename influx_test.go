@@ -0,0 +1,131 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInfluxLineEscaping(t *testing.T) {
+	s := session{
+		StartTime: time.Unix(1715000000, 0),
+		Duration:  25 * time.Minute,
+		Pauses:    2,
+		Project:   "api, v2=new",
+		Tags:      []string{"code review", "pair"},
+	}
+	got := influxLine("focus time,me", s)
+	want := `focus\ time\,me,project=api\,\ v2\=new,status=completed,tags=code\ review\,pair,type=work ` +
+		"duration=1500i,interruptions=2i 1715000000000000000"
+	if got != want {
+		t.Errorf("influxLine() = %q, want %q", got, want)
+	}
+
+	got = influxLine("pomodoro", session{StartTime: time.Unix(0, 5), Type: breakSession})
+	if want := "pomodoro,status=completed,type=break duration=0i,interruptions=0i 5"; got != want {
+		t.Errorf("influxLine() without project or tags = %q, want %q", got, want)
+	}
+}
+
+// influxServer records the lines posted to it and fails the requests
+// numbered in fail, counting from 1.
+type influxServer struct {
+	mu       sync.Mutex
+	requests int
+	fail     map[int]bool
+	lines    []string
+}
+
+func (s *influxServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if s.fail[s.requests] {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.Header.Get("Authorization") != "Token secret" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	s.lines = append(s.lines, strings.Split(strings.TrimSpace(string(body)), "\n")...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestPostInfluxResumesFromMark(t *testing.T) {
+	chdirTemp(t)
+	server := &influxServer{fail: map[int]bool{2: true}}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	cfg := influxConfig{URL: httpServer.URL, Token: "secret"}
+
+	sessions := generateSessions(influxBatch + 1)
+	posted, err := postInflux(cfg, sessions)
+	if err == nil || posted != influxBatch {
+		t.Fatalf("postInflux() with a failing second batch = %d, %v, want %d and an error", posted, err, influxBatch)
+	}
+
+	posted, err = postInflux(cfg, sessions)
+	if err != nil || posted != 1 {
+		t.Fatalf("postInflux() after the failure = %d, %v, want the one remaining session", posted, err)
+	}
+	if len(server.lines) != influxBatch+1 {
+		t.Errorf("server received %d lines, want %d", len(server.lines), influxBatch+1)
+	}
+
+	requests := server.requests
+	if posted, err := postInflux(cfg, sessions); err != nil || posted != 0 || server.requests != requests {
+		t.Errorf("postInflux() without new sessions = %d, %v after %d requests, want nothing posted",
+			posted, err, server.requests-requests)
+	}
+
+	// A long session started before the last posted one but completed after it.
+	last := sessions[len(sessions)-1]
+	long := session{StartTime: last.StartTime.Add(-time.Hour), EndTime: last.completedAt().Add(time.Minute),
+		Duration: time.Hour, Project: "late"}
+	posted, err = postInflux(cfg, append(sessions, long))
+	if err != nil || posted != 1 {
+		t.Fatalf("postInflux() with a session completed after the mark = %d, %v, want 1", posted, err)
+	}
+	if got := server.lines[len(server.lines)-1]; !strings.HasPrefix(got, "pomodoro,project=late,") {
+		t.Errorf("last line posted = %q, want the late session", got)
+	}
+}
+
+func TestPostInfluxBatchEndsBetweenEqualCompletionTimes(t *testing.T) {
+	chdirTemp(t)
+	server := &influxServer{fail: map[int]bool{2: true}}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	cfg := influxConfig{URL: httpServer.URL, Token: "secret"}
+
+	// The last two sessions complete at the same time on either side of the
+	// first batch's end.
+	sessions := generateSessions(influxBatch + 1)
+	last := sessions[influxBatch-1]
+	sessions[influxBatch] = session{StartTime: last.StartTime.Add(time.Minute), EndTime: last.completedAt(),
+		Duration: time.Minute, Project: "tied"}
+
+	if posted, err := postInflux(cfg, sessions); err == nil || posted != influxBatch {
+		t.Fatalf("postInflux() with a failing second batch = %d, %v, want %d and an error", posted, err, influxBatch)
+	}
+	if posted, err := postInflux(cfg, sessions); err != nil || posted != 1 {
+		t.Fatalf("postInflux() after the failure = %d, %v, want the other tied session", posted, err)
+	}
+	if posted, err := postInflux(cfg, sessions); err != nil || posted != 0 {
+		t.Errorf("postInflux() without new sessions = %d, %v, want nothing posted", posted, err)
+	}
+
+	seen := map[string]bool{}
+	for _, line := range server.lines {
+		seen[line] = true
+	}
+	if len(seen) != influxBatch+1 {
+		t.Errorf("server received %d distinct lines, want %d", len(seen), influxBatch+1)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const intentReportWeeks = 8
+
+var intentStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#04B575")).Render
+
+var outcomeKeys = map[string]string{
+	"y": "yes",
+	"p": "partly",
+	"n": "no",
+}
+
+// needsReview reports whether a completed session should ask if its
+// intent was achieved before it is saved.
+func (m model) needsReview() bool {
+	return m.sessionType == workSession && m.intent != "" && !m.headless
+}
+
+func (m model) intentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return m, tea.Quit
+	}
+
+	if m.askingIntent {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.intent = strings.TrimSpace(m.textarea.Value())
+			m.askingIntent = false
+			m.textarea.Reset()
+		case tea.KeyEsc:
+			m.askingIntent = false
+			m.textarea.Reset()
+		}
+		return m, nil
+	}
+
+	if msg.Type == tea.KeyEsc {
+		return m, m.endSession()
+	}
+
+	outcome, ok := outcomeKeys[msg.String()]
+	if !ok {
+		return m, nil
+	}
+	save := m.reviewSession(outcome)
+	return m, tea.Batch(save, m.endSession())
+}
+
+// intentReport shows per week how often intents were achieved, counting a
+// partly achieved intent as half. Sessions count towards the day they
+// ended on.
+func intentReport(index sessionIndex, weeks int, today time.Time) string {
+	thisWeek := weekStart(today)
+
+	result := "Intent achievement:\n\n"
+	total := outcomeCounts{}
+	for week := weeks - 1; week >= 0; week-- {
+		start := thisWeek.AddDate(0, 0, -7*week)
+		counts := index.week(start).Outcomes
+		total.merge(counts)
+
+		result += fmt.Sprintf("Week of %s: %s\n", start.Format(time.DateOnly), formatOutcomes(counts))
+	}
+	result += fmt.Sprintf("\nOverall: %s\n", formatOutcomes(total))
+
+	projects := ""
+	for _, name := range index.projectNames() {
+		counts := index.project(name).Outcomes
+		if counts == (outcomeCounts{}) {
+			continue
+		}
+		if name == "" {
+			name = "No project"
+		}
+		projects += fmt.Sprintf("%-12s %s\n", name, formatOutcomes(counts))
+	}
+	if projects != "" {
+		result += "\nAll time by project:\n" + projects
+	}
+	return result
+}
+
+func (c *outcomeCounts) merge(other outcomeCounts) {
+	c.Yes += other.Yes
+	c.Partly += other.Partly
+	c.No += other.No
+}
+
+func formatOutcomes(counts outcomeCounts) string {
+	reviewed := counts.Yes + counts.Partly + counts.No
+	if reviewed == 0 {
+		return "no reviewed intents"
+	}
+
+	rate := (float64(counts.Yes) + float64(counts.Partly)/2) / float64(reviewed) * 100
+	return fmt.Sprintf("%3.0f%% achieved (%d yes, %d partly, %d no)",
+		rate, counts.Yes, counts.Partly, counts.No)
+}
+
+// weekStart returns midnight on the Monday of the week containing t.
+func weekStart(t time.Time) time.Time {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	logFile       = "pomodoro.log"
+	maxLogSize    = 1 << 20
+	keptLogs      = 3
+	logPaneLines  = 8
+	logPaneBuffer = 200
+)
+
+// logLevel is changed by -log-level and -debug.
+var logLevel = new(slog.LevelVar)
+
+var logger = slog.New(slog.NewTextHandler(&rotatingLog{}, &slog.HandlerOptions{Level: logLevel}))
+
+// logPane keeps the latest log lines for the -debug pane.
+var logPane = &logLines{notify: make(chan struct{}, 1)}
+
+type logLineMsg struct{}
+
+// stateDir is where the log file lives, e.g. ~/.local/state/pomodoro.
+func stateDir() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return os.TempDir()
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "pomodoro")
+}
+
+// setupLogging takes debug, info, warn, error or off. With debug the log
+// is also kept for the live pane.
+func setupLogging(level string, debug bool) error {
+	writer := &rotatingLog{}
+	if debug {
+		level = "debug"
+		writer.pane = logPane
+	}
+
+	if level != "off" {
+		var parsed slog.Level
+		err := parsed.UnmarshalText([]byte(level))
+		if err != nil {
+			return fmt.Errorf("Invalid log level %q, use debug, info, warn, error or off", level)
+		}
+		logLevel.Set(parsed)
+		writer.path = filepath.Join(stateDir(), logFile)
+	}
+
+	logger = slog.New(slog.NewTextHandler(writer, &slog.HandlerOptions{Level: logLevel}))
+	return nil
+}
+
+// rotatingLog appends to path, moving it to path.1, path.2 and so on once
+// it grows past maxLogSize. The file is only created once something is
+// written, and nothing is written without a path.
+type rotatingLog struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+	pane *logLines
+}
+
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.pane != nil {
+		l.pane.add(strings.TrimSuffix(string(p), "\n"))
+	}
+	if l.path == "" {
+		return len(p), nil
+	}
+
+	if l.file != nil && l.size+int64(len(p)) > maxLogSize {
+		l.file.Close()
+		l.file = nil
+		for i := keptLogs - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+		}
+		os.Rename(l.path, l.path+".1")
+	}
+
+	if l.file == nil {
+		err := os.MkdirAll(filepath.Dir(l.path), 0755)
+		if err != nil {
+			return 0, err
+		}
+		file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return 0, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return 0, err
+		}
+		l.file = file
+		l.size = info.Size()
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// logLines is a ring of the latest log lines that wakes the UI on each
+// new one.
+type logLines struct {
+	mu     sync.Mutex
+	lines  []string
+	notify chan struct{}
+}
+
+func (l *logLines) add(line string) {
+	l.mu.Lock()
+	l.lines = append(l.lines, line)
+	if len(l.lines) > logPaneBuffer {
+		l.lines = l.lines[len(l.lines)-logPaneBuffer:]
+	}
+	l.mu.Unlock()
+
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (l *logLines) last(n int) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.lines[max(len(l.lines)-n, 0):]...)
+}
+
+// waitForLogCmd redraws the pane once a new line was logged.
+func waitForLogCmd() tea.Msg {
+	<-logPane.notify
+	return logLineMsg{}
+}
+
+func (m model) logPaneView() string {
+	if !m.debug {
+		return ""
+	}
+	// Long lines are cut to the terminal width to keep the pane's height.
+	lines := logPane.last(logPaneLines)
+	for i, line := range lines {
+		if runes := []rune(line); len(runes) > m.logView.Width {
+			lines[i] = string(runes[:max(m.logView.Width-1, 0)]) + "…"
+		}
+	}
+	return helpStyle("── log ──\n" + strings.Join(lines, "\n"))
+}
+
+// traced runs an integration call and logs how long it took.
+func traced(integration string, call func() error) error {
+	started := time.Now()
+	err := call()
+	if err != nil {
+		logger.Warn("integration failed", "integration", integration,
+			"duration", time.Since(started), "err", err)
+		return err
+	}
+	logger.Debug("integration called", "integration", integration, "duration", time.Since(started))
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -8,9 +9,31 @@ import (
 )
 
 func main() {
-	program := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	flags := flag.NewFlagSet("pomodoro", flag.ExitOnError)
+	level := flags.String("log-level", "warn", "what to log to "+logFile+" in the state directory: debug, info, warn, error or off")
+	debug := flags.Bool("debug", false, "log everything and show the log live in the timer")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), commandsUsage+"\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	if err := setupLogging(*level, *debug); err != nil {
+		fmt.Println("Oh no!", err)
+		os.Exit(1)
+	}
+
+	if flags.NArg() > 0 {
+		if err := runCommand(flags.Args()); err != nil {
+			fmt.Println("Oh no!", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	if _, err := program.Run(); err != nil {
+	m := initialModel()
+	m.debug = *debug
+	if err := runProgram(m, tea.WithAltScreen(), tea.WithReportFocus(), tea.WithOutput(terminal), tea.WithFPS(frameRate)); err != nil {
 		fmt.Println("Oh no!", err)
 		os.Exit(1)
 	}
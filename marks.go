@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const markHighlight = 3 * time.Second
+
+var markStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).
+	Background(lipgloss.Color("#F25D94")).Padding(0, 1).Render
+
+// sessionMark fires once when the remaining time drops to remaining.
+type sessionMark struct {
+	remaining time.Duration
+	label     string
+	fired     bool
+}
+
+// parseMarks turns mark specs such as "50%" (elapsed share) or "5m"
+// (time left) into marks for a session of the given length. Marks that
+// fall outside the session are dropped.
+func parseMarks(specs []string, duration time.Duration) ([]sessionMark, error) {
+	marks := []sessionMark{}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+
+		mark := sessionMark{}
+		if strings.HasSuffix(spec, "%") {
+			percent, err := strconv.ParseFloat(strings.TrimSuffix(spec, "%"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mark %q", spec)
+			}
+			mark.remaining = time.Duration(float64(duration) * (1 - percent/100)).Round(time.Second)
+			mark.label = fmt.Sprintf("%.0f%% done", percent)
+			if percent == 50 {
+				mark.label = "Halfway there"
+			}
+		} else {
+			left, err := time.ParseDuration(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mark %q", spec)
+			}
+			mark.remaining = left
+			mark.label = humanizeLeft(left)
+		}
+
+		if mark.remaining <= 0 || mark.remaining >= duration {
+			continue
+		}
+		marks = append(marks, mark)
+	}
+
+	sort.Slice(marks, func(i, j int) bool {
+		return marks[i].remaining > marks[j].remaining
+	})
+	return marks, nil
+}
+
+func humanizeLeft(left time.Duration) string {
+	switch {
+	case left >= time.Minute && left%time.Minute == 0:
+		if left == time.Minute {
+			return "1 minute left"
+		}
+		return fmt.Sprintf("%d minutes left", int(left.Minutes()))
+	case left == time.Second:
+		return "1 second left"
+	case left%time.Second == 0 && left < time.Minute:
+		return fmt.Sprintf("%d seconds left", int(left.Seconds()))
+	}
+	return left.String() + " left"
+}
+
+// markSpecs returns the marks configured for the preset's work sessions,
+// falling back to those for the session type.
+func (m model) markSpecs() []string {
+	if p, ok := m.config.Presets[m.preset]; ok && p.Marks != nil && m.sessionType == workSession {
+		return p.Marks
+	}
+	return m.config.Marks[strings.ToLower(m.sessionType)]
+}
+
+// markerRow draws a tick under the progress bar at each mark position.
+func markerRow(marks []sessionMark, duration time.Duration, width int) string {
+	// The progress bar shares its width with the percentage, e.g. " 50%".
+	barWidth := width - 5
+	if barWidth <= 0 || len(marks) == 0 {
+		return ""
+	}
+
+	row := []rune(strings.Repeat(" ", barWidth))
+	for _, mark := range marks {
+		position := int(float64(barWidth) * (1 - float64(mark.remaining)/float64(duration)))
+		position = min(max(position, 0), barWidth-1)
+		row[position] = '▴'
+	}
+	return string(row)
+}
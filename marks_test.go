@@ -0,0 +1,136 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkSpecsScopesPresetsToWork(t *testing.T) {
+	m := model{preset: "deep", config: config{
+		Marks:   map[string][]string{"work": {"5m"}, "break": {"1m"}},
+		Presets: map[string]preset{"deep": {Work: 90, Break: 20, Marks: []string{"50%"}}},
+	}}
+
+	m.sessionType = workSession
+	if got := m.markSpecs(); !slices.Equal(got, []string{"50%"}) {
+		t.Errorf("work marks = %v, want the preset's", got)
+	}
+	m.sessionType = breakSession
+	if got := m.markSpecs(); !slices.Equal(got, []string{"1m"}) {
+		t.Errorf("break marks = %v, want the break marks", got)
+	}
+}
+
+func TestAlertCmdOnlyForMarks(t *testing.T) {
+	m := model{config: config{Notify: []string{"true"}, Sound: []string{"true"}}}
+	if cmd := alertCmd(m, lifecycleEvent{Kind: eventComplete, SessionType: workSession}); cmd != nil {
+		t.Error("alertCmd() alerts on completion")
+	}
+	if cmd := alertCmd(m, lifecycleEvent{Kind: eventMark, SessionType: workSession, Mark: "1 minute left"}); cmd == nil {
+		t.Error("alertCmd() is silent at a mark")
+	}
+}
+
+func TestParseMarks(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []sessionMark
+		wantErr bool
+	}{
+		{name: "none", specs: nil, want: []sessionMark{}},
+		{
+			name:  "sorted by time left",
+			specs: []string{"5m", " 50% ", "20%", "30s"},
+			want: []sessionMark{
+				{remaining: 20 * time.Minute, label: "20% done"},
+				{remaining: 12*time.Minute + 30*time.Second, label: "Halfway there"},
+				{remaining: 5 * time.Minute, label: "5 minutes left"},
+				{remaining: 30 * time.Second, label: "30 seconds left"},
+			},
+		},
+		{
+			name:  "past the session length",
+			specs: []string{"25m", "30m", "0%", "100%", "150%", "-10%", "0s", "-5m", "1m"},
+			want:  []sessionMark{{remaining: time.Minute, label: "1 minute left"}},
+		},
+		{name: "empty", specs: []string{""}, wantErr: true},
+		{name: "bare percent", specs: []string{"%"}, wantErr: true},
+		{name: "words", specs: []string{"half%"}, wantErr: true},
+		{name: "unknown unit", specs: []string{"5x"}, wantErr: true},
+		{name: "no unit", specs: []string{"5"}, wantErr: true},
+		{name: "invalid after valid", specs: []string{"5m", "soon"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseMarks(test.specs, 25*time.Minute)
+		if (err != nil) != test.wantErr || !slices.Equal(got, test.want) {
+			t.Errorf("%s: parseMarks(%q) = %v, %v, want %v, error %v",
+				test.name, test.specs, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestHumanizeLeft(t *testing.T) {
+	tests := []struct {
+		left time.Duration
+		want string
+	}{
+		{time.Minute, "1 minute left"},
+		{5 * time.Minute, "5 minutes left"},
+		{2 * time.Hour, "120 minutes left"},
+		{time.Second, "1 second left"},
+		{45 * time.Second, "45 seconds left"},
+		{90 * time.Second, "1m30s left"},
+		{1500 * time.Millisecond, "1.5s left"},
+	}
+	for _, test := range tests {
+		if got := humanizeLeft(test.left); got != test.want {
+			t.Errorf("humanizeLeft(%v) = %q, want %q", test.left, got, test.want)
+		}
+	}
+}
+
+func TestMarkerRow(t *testing.T) {
+	tests := []struct {
+		name  string
+		marks []time.Duration
+		width int
+		want  []int
+	}{
+		{name: "no marks", width: 25},
+		{name: "too narrow", marks: []time.Duration{5 * time.Minute}, width: 5},
+		{name: "halfway", marks: []time.Duration{5 * time.Minute}, width: 25, want: []int{10}},
+		{name: "several", marks: []time.Duration{7*time.Minute + 30*time.Second, 150 * time.Second}, width: 25, want: []int{5, 15}},
+		{name: "clamped to the bar", marks: []time.Duration{0, 15 * time.Minute}, width: 25, want: []int{0, 19}},
+	}
+
+	for _, test := range tests {
+		marks := []sessionMark{}
+		for _, remaining := range test.marks {
+			marks = append(marks, sessionMark{remaining: remaining})
+		}
+
+		row := markerRow(marks, 10*time.Minute, test.width)
+		if test.want == nil {
+			if row != "" {
+				t.Errorf("%s: markerRow() = %q, want no row", test.name, row)
+			}
+			continue
+		}
+
+		runes := []rune(row)
+		got := []int{}
+		for i, r := range runes {
+			if r == '▴' {
+				got = append(got, i)
+			}
+		}
+		if len(runes) != test.width-5 || !slices.Equal(got, test.want) || strings.Trim(row, " ▴") != "" {
+			t.Errorf("%s: markerRow() = %q with ticks at %v, want %d wide with ticks at %v",
+				test.name, row, got, test.width-5, test.want)
+		}
+	}
+}
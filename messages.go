@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	maxToasts   = 3
+	maxMessages = 500
+	// messagesHeight is the log screen's height until the terminal size is
+	// known; messagesChrome the lines around it.
+	messagesHeight = 20
+	messagesChrome = 6
+)
+
+// Toasts of higher severity stay up longer. Debug messages are only logged.
+var toastTimeouts = map[slog.Level]time.Duration{
+	slog.LevelInfo:  3 * time.Second,
+	slog.LevelWarn:  6 * time.Second,
+	slog.LevelError: 10 * time.Second,
+}
+
+var toastStyles = map[slog.Level]func(...string) string{
+	slog.LevelDebug: helpStyle,
+	slog.LevelInfo:  lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575")).Render,
+	slog.LevelWarn:  lipgloss.NewStyle().Foreground(lipgloss.Color("#FFB86C")).Render,
+	slog.LevelError: overrunStyle,
+}
+
+var toastIcons = map[slog.Level]string{
+	slog.LevelDebug: "·",
+	slog.LevelInfo:  "ℹ",
+	slog.LevelWarn:  "⚠",
+	slog.LevelError: "✖",
+}
+
+type message struct {
+	level slog.Level
+	text  string
+	at    time.Time
+}
+
+func (msg message) String() string {
+	return fmt.Sprintf("%s %-5s %s", msg.at.Format(time.DateTime), msg.level, msg.text)
+}
+
+type toast struct {
+	message
+	until time.Time
+}
+
+type toastExpiredMsg struct{}
+
+// toast shows text for a while, stacked under earlier toasts, and records
+// it in the message log.
+func (m *model) toast(level slog.Level, text string) {
+	msg := message{level: level, text: text, at: time.Now()}
+	logger.Log(context.Background(), level, text)
+
+	m.messages = append(m.messages, msg)
+	if len(m.messages) > maxMessages {
+		m.messages = m.messages[len(m.messages)-maxMessages:]
+	}
+	if m.showMessages {
+		m.logView.SetContent(m.messagesContent())
+		m.logView.GotoBottom()
+	}
+
+	if level < slog.LevelInfo {
+		return
+	}
+	m.toasts = append(m.toasts, toast{message: msg, until: msg.at.Add(toastTimeouts[level])})
+	if len(m.toasts) > maxToasts {
+		m.toasts = m.toasts[len(m.toasts)-maxToasts:]
+	}
+}
+
+// syncToasts wakes up when the next toast times out.
+func (m *model) syncToasts() tea.Cmd {
+	if len(m.toasts) == 0 || m.toastWaking {
+		return nil
+	}
+
+	next := m.toasts[0].until
+	for _, t := range m.toasts {
+		if t.until.Before(next) {
+			next = t.until
+		}
+	}
+
+	m.toastWaking = true
+	return tea.Tick(time.Until(next), func(time.Time) tea.Msg {
+		return toastExpiredMsg{}
+	})
+}
+
+func (m *model) expireToasts() {
+	m.toastWaking = false
+
+	now := time.Now()
+	toasts := []toast{}
+	for _, t := range m.toasts {
+		if t.until.After(now) {
+			toasts = append(toasts, t)
+		}
+	}
+	m.toasts = toasts
+}
+
+func (m model) toastsView() string {
+	lines := []string{}
+	for _, t := range m.toasts {
+		lines = append(lines, toastStyles[t.level](toastIcons[t.level]+" "+t.text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// openMessages shows the message log scrolled to the newest message.
+func (m *model) openMessages() {
+	m.showMessages = true
+	m.logView.SetContent(m.messagesContent())
+	m.logView.GotoBottom()
+}
+
+func (m model) messagesContent() string {
+	if len(m.messages) == 0 {
+		return "No messages yet."
+	}
+
+	lines := []string{}
+	for _, msg := range m.messages {
+		lines = append(lines, toastStyles[msg.level](msg.String()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m model) messagesView() string {
+	return fmt.Sprintf("\nMessages\n\n%s\n\n%s",
+		m.logView.View(),
+		helpStyle(" - Use ↑/↓ and PgUp/PgDn to scroll, press 'x' to close\n"))
+}
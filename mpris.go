@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	mprisPrefix      = "org.mpris.MediaPlayer2."
+	mprisPath        = "/org/mpris/MediaPlayer2"
+	mprisPlayerIface = "org.mpris.MediaPlayer2.Player"
+)
+
+var mprisMethods = map[string]string{
+	"play":     "Play",
+	"pause":    "Pause",
+	"toggle":   "PlayPause",
+	"stop":     "Stop",
+	"next":     "Next",
+	"skip":     "Next",
+	"previous": "Previous",
+}
+
+// Music plays during work and pauses otherwise unless configured differently.
+var defaultMprisActions = map[string]string{
+	"work_start":    "play",
+	"work_complete": "pause",
+	"work_abandon":  "pause",
+	"work_pause":    "pause",
+	"work_resume":   "play",
+}
+
+func mprisCmd(cfg mprisConfig, event lifecycleEvent, playlist string) tea.Cmd {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	actions := cfg.Actions
+	if actions == nil {
+		actions = defaultMprisActions
+	}
+
+	action, ok := actions[event.name()]
+	if !ok || action == "" || action == "none" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		err := traced("mpris", func() error {
+			return mprisControl(cfg.Player, action, playlist)
+		})
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error controlling media player: %v", err)}
+		}
+		return nil
+	}
+}
+
+// mprisControl runs action on the configured player, or on the first player
+// found on the session bus. Playing with a playlist opens it first.
+func mprisControl(player string, action string, playlist string) error {
+	method, ok := mprisMethods[action]
+	if !ok {
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+
+	name, err := findMprisPlayer(conn, player)
+	if err != nil {
+		return err
+	}
+
+	obj := conn.Object(name, mprisPath)
+	if method == "Play" && playlist != "" {
+		err = obj.Call(mprisPlayerIface+".OpenUri", 0, playlist).Err
+		if err != nil {
+			return err
+		}
+	}
+
+	return obj.Call(mprisPlayerIface+"."+method, 0).Err
+}
+
+func findMprisPlayer(conn *dbus.Conn, player string) (string, error) {
+	names := []string{}
+	err := conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, mprisPrefix) {
+			continue
+		}
+		if player == "" {
+			return name, nil
+		}
+
+		// Players with multiple instances register e.g. "vlc.instance1234".
+		instance := strings.TrimPrefix(name, mprisPrefix)
+		if instance == player || strings.HasPrefix(instance, player+".") {
+			return name, nil
+		}
+	}
+
+	if player == "" {
+		return "", fmt.Errorf("no media player found")
+	}
+	return "", fmt.Errorf("media player %q not found", player)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+var testBus struct {
+	once    sync.Once
+	dir     string
+	daemon  *exec.Cmd
+	address string
+	err     error
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if testBus.daemon != nil {
+		testBus.daemon.Process.Kill()
+		testBus.daemon.Wait()
+		os.RemoveAll(testBus.dir)
+	}
+	os.Exit(code)
+}
+
+// privateBus starts a dbus-daemon for the test run and points the session
+// bus at it, skipping the test when dbus-daemon is not installed. The
+// daemon is shared because dbus.SessionBus connects only once.
+func privateBus(t *testing.T) string {
+	t.Helper()
+	testBus.once.Do(func() {
+		testBus.err = startBus()
+	})
+	if testBus.err != nil {
+		t.Skipf("no private session bus: %v", testBus.err)
+	}
+	return testBus.address
+}
+
+func startBus() error {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		return err
+	}
+
+	testBus.dir, err = os.MkdirTemp("", "pomodoro-bus")
+	if err != nil {
+		return err
+	}
+	socket := filepath.Join(testBus.dir, "bus")
+	config := filepath.Join(testBus.dir, "session.conf")
+	err = os.WriteFile(config, []byte(`<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=`+socket+`</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`), 0644)
+	if err != nil {
+		return err
+	}
+
+	testBus.daemon = exec.Command(daemon, "--config-file="+config, "--nofork")
+	err = testBus.daemon.Start()
+	if err != nil {
+		return err
+	}
+
+	testBus.address = "unix:path=" + socket
+	os.Setenv("DBUS_SESSION_BUS_ADDRESS", testBus.address)
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		conn, err := dbus.Connect(testBus.address)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return fmt.Errorf("dbus-daemon did not start listening on %s", socket)
+}
+
+// fakePlayer records the MPRIS player methods called on it.
+type fakePlayer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *fakePlayer) record(call string) *dbus.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+	return nil
+}
+
+func (p *fakePlayer) Play() *dbus.Error      { return p.record("Play") }
+func (p *fakePlayer) Pause() *dbus.Error     { return p.record("Pause") }
+func (p *fakePlayer) PlayPause() *dbus.Error { return p.record("PlayPause") }
+func (p *fakePlayer) Stop() *dbus.Error      { return p.record("Stop") }
+func (p *fakePlayer) Next() *dbus.Error      { return p.record("Next") }
+func (p *fakePlayer) Previous() *dbus.Error  { return p.record("Previous") }
+
+func (p *fakePlayer) OpenUri(uri string) *dbus.Error {
+	return p.record("OpenUri " + uri)
+}
+
+func (p *fakePlayer) takeCalls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	calls := p.calls
+	p.calls = nil
+	return calls
+}
+
+// startFakePlayer registers a player as org.mpris.MediaPlayer2.<name>.
+func startFakePlayer(t *testing.T, address string, name string) *fakePlayer {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	player := &fakePlayer{}
+	err = conn.Export(player, mprisPath, mprisPlayerIface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := conn.RequestName(mprisPrefix+name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName(%s) = %v, %v", name, reply, err)
+	}
+	return player
+}
+
+func TestMprisControl(t *testing.T) {
+	address := privateBus(t)
+	vlc := startFakePlayer(t, address, "vlc.instance42")
+
+	tests := []struct {
+		player   string
+		action   string
+		playlist string
+		want     []string
+	}{
+		{"vlc", "play", "", []string{"Play"}},
+		{"vlc", "play", "file:///music/focus.m3u", []string{"OpenUri file:///music/focus.m3u", "Play"}},
+		{"vlc", "pause", "file:///music/focus.m3u", []string{"Pause"}},
+		{"vlc", "toggle", "", []string{"PlayPause"}},
+		{"vlc", "skip", "", []string{"Next"}},
+		{"vlc", "previous", "", []string{"Previous"}},
+		{"vlc.instance42", "stop", "", []string{"Stop"}},
+		{"", "next", "", []string{"Next"}},
+	}
+	for _, test := range tests {
+		err := mprisControl(test.player, test.action, test.playlist)
+		if err != nil {
+			t.Errorf("mprisControl(%q, %q) = %v", test.player, test.action, err)
+			continue
+		}
+		if calls := vlc.takeCalls(); !slices.Equal(calls, test.want) {
+			t.Errorf("mprisControl(%q, %q, %q) called %v, want %v",
+				test.player, test.action, test.playlist, calls, test.want)
+		}
+	}
+}
+
+func TestMprisControlErrors(t *testing.T) {
+	address := privateBus(t)
+	startFakePlayer(t, address, "spotify")
+
+	if err := mprisControl("rhythmbox", "play", ""); err == nil {
+		t.Error("mprisControl() with a missing player succeeded")
+	}
+	if err := mprisControl("spotify", "rewind", ""); err == nil {
+		t.Error("mprisControl() with an unknown action succeeded")
+	}
+}
+
+func TestMprisCmdDefaultActions(t *testing.T) {
+	address := privateBus(t)
+	player := startFakePlayer(t, address, "fake")
+	cfg := mprisConfig{Enabled: true, Player: "fake"}
+
+	tests := []struct {
+		event lifecycleEvent
+		want  []string
+	}{
+		{lifecycleEvent{Kind: eventStart, SessionType: workSession}, []string{"OpenUri spotify:playlist:focus", "Play"}},
+		{lifecycleEvent{Kind: eventComplete, SessionType: workSession}, []string{"Pause"}},
+		{lifecycleEvent{Kind: eventStart, SessionType: breakSession}, nil},
+	}
+	for _, test := range tests {
+		cmd := mprisCmd(cfg, test.event, "spotify:playlist:focus")
+		if cmd != nil {
+			if msg, ok := cmd().(integrationErrMsg); ok {
+				t.Fatalf("%s: %v", test.event.name(), msg.err)
+			}
+		}
+		if calls := player.takeCalls(); !slices.Equal(calls, test.want) {
+			t.Errorf("%s called %v, want %v", test.event.name(), calls, test.want)
+		}
+	}
+
+	if cmd := mprisCmd(mprisConfig{}, tests[0].event, ""); cmd != nil {
+		t.Error("mprisCmd() runs while MPRIS is disabled")
+	}
+}
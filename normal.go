@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const normalHelp = `
+ s start work   b take a break   l list today   c calendar   i intents   m mute
+
+ : command line   ? full help   q quit
+`
+
+// normalKeys act immediately in normal mode, like typing the command.
+var normalKeys = map[string]bool{
+	"s": true,
+	"b": true,
+	"l": true,
+	"c": true,
+	"i": true,
+	"m": true,
+	"q": true,
+}
+
+func (m model) normalMode() bool {
+	return m.config.Mode == "normal"
+}
+
+// normalKey handles a key in the idle view of normal mode, where ':' opens
+// the command line for commands that take arguments.
+func (m model) normalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	key := msg.String()
+	switch {
+	case key == ":":
+		m.commandLine = true
+		return m, nil
+	case key == "?":
+		m.showHelp = !m.showHelp
+		return m, nil
+	case key == "p":
+		m.toast(slog.LevelWarn, "No session to pause")
+		return m, nil
+	case key == "ctrl+c" || key == "esc":
+		return m, tea.Quit
+	case normalKeys[key]:
+		return m.execCommand(key)
+	}
+	return m, nil
+}
+
+// modeFooter names the current input mode.
+func (m model) modeFooter() string {
+	if m.normalMode() && !m.commandLine && !m.prompting {
+		return helpStyle(" -- NORMAL --")
+	}
+	return helpStyle(" -- COMMAND --")
+}
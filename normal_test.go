@@ -0,0 +1,147 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func normalModel() model {
+	m := initialModel()
+	m.loading = false
+	m.config.Mode = "normal"
+	return m
+}
+
+// press sends keys to m one after another, where "esc" and "enter" are the
+// named keys and anything else is typed.
+func press(m model, keys ...string) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "ctrl+c":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlC}
+		}
+		var next tea.Model
+		next, cmd = m.update(msg)
+		m = next.(model)
+	}
+	return m, cmd
+}
+
+func TestNormalKeysStartSessions(t *testing.T) {
+	chdirTemp(t)
+
+	tests := []struct {
+		key         string
+		sessionType string
+		minutes     float64
+	}{
+		{key: "s", sessionType: workSession, minutes: defaultWorkMinutes},
+		{key: "b", sessionType: breakSession, minutes: defaultBreakMinutes},
+	}
+	for _, test := range tests {
+		m, _ := press(normalModel(), test.key)
+		if !m.inSession || m.sessionType != test.sessionType || m.timerDuration.Minutes() != test.minutes {
+			t.Errorf("%q: in session = %v, running %s for %v, want %s for %vm",
+				test.key, m.inSession, m.sessionType, m.timerDuration, test.sessionType, test.minutes)
+		}
+		if m.textarea.Value() != "" {
+			t.Errorf("%q: left %q in the input", test.key, m.textarea.Value())
+		}
+	}
+}
+
+func TestNormalKeysToggleViews(t *testing.T) {
+	chdirTemp(t)
+
+	tests := []struct {
+		key   string
+		shown func(m model) bool
+	}{
+		{key: "?", shown: func(m model) bool { return m.showHelp }},
+		{key: "m", shown: func(m model) bool { return m.muted }},
+		{key: "i", shown: func(m model) bool { return m.showIntents }},
+		{key: "l", shown: func(m model) bool { return m.showSession }},
+		{key: "c", shown: func(m model) bool { return m.showCalendar }},
+	}
+	for _, test := range tests {
+		m, _ := press(normalModel(), test.key)
+		if !test.shown(m) || m.inSession {
+			t.Errorf("%q did not act, or started a session", test.key)
+		}
+	}
+
+	m, _ := press(normalModel(), "?", "?")
+	if m.showHelp {
+		t.Error("'?' twice leaves the full help open")
+	}
+}
+
+func TestNormalKeysQuit(t *testing.T) {
+	chdirTemp(t)
+
+	for _, k := range []string{"q", "esc", "ctrl+c"} {
+		if _, cmd := press(normalModel(), k); !isQuit(cmd) {
+			t.Errorf("%q does not quit", k)
+		}
+	}
+}
+
+func TestNormalKeysIgnoreOthers(t *testing.T) {
+	chdirTemp(t)
+
+	m, cmd := press(normalModel(), "x")
+	if cmd != nil || m.inSession || m.commandLine || m.textarea.Value() != "" {
+		t.Errorf("'x' acted: cmd = %v, in session = %v, command line = %v, input = %q",
+			cmd != nil, m.inSession, m.commandLine, m.textarea.Value())
+	}
+
+	m, _ = press(normalModel(), "p")
+	if len(m.messages) == 0 || m.messages[len(m.messages)-1].text != "No session to pause" {
+		t.Error("'p' without a session does not warn")
+	}
+}
+
+func TestNormalCommandLine(t *testing.T) {
+	chdirTemp(t)
+
+	m, _ := press(normalModel(), ":", "s", " ", "1", "0")
+	if !m.commandLine || m.inSession || m.textarea.Value() != "s 10" {
+		t.Fatalf("after ':s 10': command line = %v, in session = %v, input = %q, want the typed command",
+			m.commandLine, m.inSession, m.textarea.Value())
+	}
+
+	m, _ = press(m, "enter")
+	if m.commandLine || !m.inSession || m.timerDuration.Minutes() != 10 {
+		t.Errorf("after Enter: command line = %v, running for %v, want a 10 minute session",
+			m.commandLine, m.timerDuration)
+	}
+
+	m, cmd := press(normalModel(), ":", "esc")
+	if m.commandLine || isQuit(cmd) {
+		t.Error("Esc on the command line does not go back to normal mode")
+	}
+	if m, _ = press(m, "s"); !m.inSession {
+		t.Error("keys don't act again after leaving the command line")
+	}
+}
+
+func TestNormalKeysDuringSession(t *testing.T) {
+	chdirTemp(t)
+
+	m, _ := press(normalModel(), "b")
+	m.opening = false
+	m, _ = press(m, "s")
+	if m.sessionType != breakSession {
+		t.Error("'s' during a break started a work session")
+	}
+	if m, _ = press(m, "p"); !m.paused {
+		t.Error("'p' during a session does not pause")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notifyCmd shows a desktop notification through the configured command,
+// e.g. ["notify-send", "-a", "pomodoro"], with title and body appended.
+func notifyCmd(command []string, title string, body string) tea.Cmd {
+	if len(command) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		args := append(command[1:len(command):len(command)], title, body)
+		err := traced("notify", exec.Command(command[0], args...).Run)
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error sending notification: %v", err)}
+		}
+		return nil
+	}
+}
+
+// soundCmd plays a sound through the configured command, e.g.
+// ["paplay", "/usr/share/sounds/freedesktop/stereo/bell.oga"].
+func soundCmd(command []string) tea.Cmd {
+	if len(command) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		err := traced("sound", exec.Command(command[0], command[1:]...).Run)
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error playing sound: %v", err)}
+		}
+		return nil
+	}
+}
+
+// alertCmd notifies and plays a sound when a warning mark is reached.
+func alertCmd(m model, event lifecycleEvent) tea.Cmd {
+	if event.Kind != eventMark {
+		return nil
+	}
+
+	return tea.Batch(
+		notifyCmd(m.config.Notify, fmt.Sprintf("%s session", event.SessionType), event.Mark),
+		soundCmd(m.config.Sound),
+	)
+}
+
+// bellCmd rings the terminal bell on out.
+func bellCmd(out io.Writer) tea.Cmd {
+	return func() tea.Msg {
+		_, err := io.WriteString(out, "\a")
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error ringing the bell: %v", err)}
+		}
+		return nil
+	}
+}
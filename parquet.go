@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	defaultParquetFile = "sessions.parquet"
+	defaultParquetDir  = "sessions"
+)
+
+// parquetSession is one row of the Parquet export. Optional columns hold
+// null where the session has a zero value, and step is null outside a
+// sequence. The end time is kept as microseconds since parquet-go writes a
+// zero time.Time as year 1.
+type parquetSession struct {
+	StartTime   time.Time `parquet:"start_time,timestamp(microsecond)"`
+	EndTime     int64     `parquet:"end_time,timestamp(microsecond),optional"`
+	DurationSec int64     `parquet:"duration_sec"`
+	Pauses      int32     `parquet:"pauses"`
+	Project     string    `parquet:"project,optional"`
+	Tags        []string  `parquet:"tags,list"`
+	Type        string    `parquet:"type"`
+	Sequence    string    `parquet:"sequence,optional"`
+	Step        *int32    `parquet:"step,optional"`
+	Intent      string    `parquet:"intent,optional"`
+	Outcome     string    `parquet:"outcome,optional"`
+	Posture     string    `parquet:"posture,optional"`
+}
+
+func parquetRow(s session) parquetSession {
+	sessionType := workSession
+	if !s.isWork() {
+		sessionType = breakSession
+	}
+
+	row := parquetSession{
+		StartTime:   s.StartTime.UTC(),
+		DurationSec: int64(s.Duration.Seconds()),
+		Pauses:      int32(s.Pauses),
+		Project:     s.Project,
+		Tags:        s.Tags,
+		Type:        sessionType,
+		Sequence:    s.Sequence,
+		Intent:      s.Intent,
+		Outcome:     s.Outcome,
+		Posture:     s.Posture,
+	}
+	if s.Sequence != "" {
+		step := int32(s.Step)
+		row.Step = &step
+	}
+	if !s.EndTime.IsZero() {
+		row.EndTime = s.EndTime.UnixMicro()
+	}
+	if row.Tags == nil {
+		row.Tags = []string{}
+	}
+	return row
+}
+
+// exportParquet writes sessions to one file, or with partition to a
+// year=YYYY/month=MM directory tree under output that pandas, DuckDB and
+// Spark read as a partitioned dataset.
+func exportParquet(sessions []session, output string, partition bool) error {
+	if !partition {
+		if output == "" {
+			output = defaultParquetFile
+		}
+		return writeParquet(output, sessions)
+	}
+
+	if output == "" {
+		output = defaultParquetDir
+	}
+
+	partitions := map[string][]session{}
+	for _, s := range sessions {
+		start := s.StartTime.Local()
+		dir := filepath.Join(output, fmt.Sprintf("year=%d", start.Year()), fmt.Sprintf("month=%02d", start.Month()))
+		partitions[dir] = append(partitions[dir], s)
+	}
+
+	for dir, sessions := range partitions {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return fmt.Errorf("Error writing to file: %v", err.Error())
+		}
+		err = writeParquet(filepath.Join(dir, "sessions.parquet"), sessions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeParquet(path string, sessions []session) error {
+	rows := make([]parquetSession, 0, len(sessions))
+	for _, s := range sessions {
+		rows = append(rows, parquetRow(s))
+	}
+
+	err := parquet.WriteFile(path, rows, parquet.Compression(&parquet.Zstd))
+	if err != nil {
+		return fmt.Errorf("Error writing to file: %v", err.Error())
+	}
+	return nil
+}
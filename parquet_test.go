@@ -0,0 +1,185 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+var parquetTestSessions = []session{
+	{
+		StartTime: time.Date(2024, 5, 6, 9, 0, 0, 123456000, time.UTC),
+		EndTime:   time.Date(2024, 5, 6, 9, 27, 30, 0, time.UTC),
+		Duration:  25 * time.Minute,
+		Pauses:    2,
+		Project:   "api",
+		Tags:      []string{"review", "pairing"},
+		Sequence:  "50w 10b",
+		Step:      1,
+		Intent:    "ship the parser",
+		Outcome:   "partly",
+		Posture:   standing,
+	},
+	{
+		// Recorded before end times were saved, outside a sequence.
+		StartTime: time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC),
+		Duration:  5 * time.Minute,
+		Type:      breakSession,
+	},
+	{
+		StartTime: time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 6, 1, 8, 50, 0, 0, time.UTC),
+		Duration:  50 * time.Minute,
+		Sequence:  "50w",
+		Step:      0,
+	},
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.parquet")
+	if err := writeParquet(path, parquetTestSessions); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.ReadFile[parquetSession](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(parquetTestSessions) {
+		t.Fatalf("read %d rows, want %d", len(rows), len(parquetTestSessions))
+	}
+
+	step := func(n int32) *int32 { return &n }
+	want := []parquetSession{
+		{
+			StartTime:   time.Date(2024, 5, 6, 9, 0, 0, 123456000, time.UTC),
+			EndTime:     time.Date(2024, 5, 6, 9, 27, 30, 0, time.UTC).UnixMicro(),
+			DurationSec: 1500,
+			Pauses:      2,
+			Project:     "api",
+			Tags:        []string{"review", "pairing"},
+			Type:        workSession,
+			Sequence:    "50w 10b",
+			Step:        step(1),
+			Intent:      "ship the parser",
+			Outcome:     "partly",
+			Posture:     standing,
+		},
+		{
+			StartTime:   time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC),
+			DurationSec: 300,
+			Tags:        []string{},
+			Type:        breakSession,
+		},
+		{
+			StartTime:   time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+			EndTime:     time.Date(2024, 6, 1, 8, 50, 0, 0, time.UTC).UnixMicro(),
+			DurationSec: 3000,
+			Tags:        []string{},
+			Type:        workSession,
+			Sequence:    "50w",
+			Step:        step(0),
+		},
+	}
+	for i, row := range rows {
+		if row.Tags == nil {
+			row.Tags = []string{}
+		}
+		row.StartTime = row.StartTime.UTC()
+		if !reflect.DeepEqual(row, want[i]) {
+			t.Errorf("row %d = %+v, want %+v", i, row, want[i])
+		}
+	}
+}
+
+// TestParquetNulls reads the raw columns, where a null and a zero differ.
+func TestParquetNulls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.parquet")
+	if err := writeParquet(path, parquetTestSessions); err != nil {
+		t.Fatal(err)
+	}
+
+	values := readParquetColumns(t, path)
+	nulls := func(column string) []bool {
+		result := []bool{}
+		for _, value := range values[column] {
+			result = append(result, value.IsNull())
+		}
+		return result
+	}
+
+	for column, want := range map[string][]bool{
+		"end_time": {false, true, false},
+		"step":     {false, true, false},
+		"project":  {false, true, true},
+		"intent":   {false, true, true},
+		"type":     {false, false, false},
+	} {
+		if got := nulls(column); !slices.Equal(got, want) {
+			t.Errorf("%s nulls = %v, want %v", column, got, want)
+		}
+	}
+
+	if step := values["step"][2]; step.IsNull() || step.Int32() != 0 {
+		t.Errorf("step of a first sequence step = %v, want 0", step)
+	}
+}
+
+func TestParquetPartitions(t *testing.T) {
+	dir := t.TempDir()
+	if err := exportParquet(parquetTestSessions, dir, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, want := range map[string]int{
+		"year=2024/month=05/sessions.parquet": 2,
+		"year=2024/month=06/sessions.parquet": 1,
+	} {
+		rows, err := parquet.ReadFile[parquetSession](filepath.Join(dir, file))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if len(rows) != want {
+			t.Errorf("%s has %d rows, want %d", file, len(rows), want)
+		}
+	}
+}
+
+// readParquetColumns returns the top level values of every row by column.
+func readParquetColumns(t *testing.T, path string) map[string][]parquet.Value {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	schema := parquet.SchemaOf(parquetSession{})
+	reader := parquet.NewReader(file, schema)
+	defer reader.Close()
+
+	columns := map[string][]parquet.Value{}
+	buf := make([]parquet.Row, 16)
+	n, err := reader.ReadRows(buf)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	for _, row := range buf[:n] {
+		for _, path := range []string{"end_time", "step", "project", "intent", "type"} {
+			leaf, _ := schema.Lookup(path)
+			for _, value := range row {
+				if value.Column() == leaf.ColumnIndex {
+					columns[path] = append(columns[path], value)
+				}
+			}
+		}
+	}
+	return columns
+}
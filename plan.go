@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const planFile = "plan.json"
+
+// planItem is a task planned for a day, or a blocker noted on that day.
+type planItem struct {
+	Date    string `json:"date"`
+	Text    string `json:"text"`
+	Blocker bool   `json:"blocker,omitempty"`
+}
+
+func loadPlan() ([]planItem, error) {
+	items := []planItem{}
+	err := readJSON(planFile, &items)
+	if err != nil {
+		return []planItem{}, err
+	}
+
+	return items, nil
+}
+
+func savePlan(items []planItem) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error marshal data: %v", err.Error())
+	}
+
+	err = writeFile(planFile, data)
+	if err != nil {
+		return fmt.Errorf("Error writing to file: %v", err.Error())
+	}
+
+	return nil
+}
+
+func addPlanItem(items []planItem, text string, blocker bool) ([]planItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return items, fmt.Errorf("Nothing to plan")
+	}
+
+	return append(items, planItem{
+		Date:    time.Now().Format(time.DateOnly),
+		Text:    text,
+		Blocker: blocker,
+	}), nil
+}
+
+func planFor(items []planItem, date time.Time, blocker bool) []string {
+	result := []string{}
+	for _, item := range items {
+		if item.Date == date.Format(time.DateOnly) && item.Blocker == blocker {
+			result = append(result, item.Text)
+		}
+	}
+	return result
+}
+
+func runPlan(args []string) error {
+	flags := newFlagSet("plan")
+	blocker := flags.Bool("blocker", false, "note a blocker instead of a task")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	items, err := loadPlan()
+	if err != nil {
+		return err
+	}
+
+	items, err = addPlanItem(items, strings.Join(flags.Args(), " "), *blocker)
+	if err != nil {
+		return err
+	}
+	return savePlan(items)
+}
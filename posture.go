@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	sitting  = "sitting"
+	standing = "standing"
+)
+
+var postureKeys = map[string]string{
+	"s": sitting,
+	"t": standing,
+}
+
+func otherPosture(posture string) string {
+	if posture == sitting {
+		return standing
+	}
+	return sitting
+}
+
+// lastPosture returns the posture of the most recent work session that
+// recorded one.
+func lastPosture(sessions []session) string {
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if sessions[i].isWork() && sessions[i].Posture != "" {
+			return sessions[i].Posture
+		}
+	}
+	return ""
+}
+
+// choosePosture decides the posture of a starting work session: asking
+// for it, or alternating from the previous one.
+func (m *model) choosePosture() {
+	switch m.config.Posture {
+	case "alternate":
+		m.posture = otherPosture(m.posture)
+	case "ask":
+		m.askingPosture = !m.headless
+	}
+}
+
+func (m model) postureKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return m, tea.Quit
+	}
+
+	if msg.Type == tea.KeyEsc {
+		m.askingPosture = false
+		m.posture = ""
+		m.textarea.Reset()
+		return m, nil
+	}
+
+	posture, ok := postureKeys[msg.String()]
+	if !ok {
+		return m, nil
+	}
+
+	m.posture = posture
+	m.askingPosture = false
+	m.textarea.Reset()
+	return m, nil
+}
+
+// postureReminder asks to switch posture when a break starts.
+func (m model) postureReminder() tea.Cmd {
+	if m.sessionType != breakSession || m.config.Posture == "" || m.posture == "" {
+		return nil
+	}
+
+	return notifyCmd(m.config.Notify, "Time to switch",
+		fmt.Sprintf("Try %s for the next session", otherPosture(m.posture)))
+}
+
+// postureTotals sums focus time per posture for one day.
+func postureTotals(sessions []session) map[string]time.Duration {
+	totals := map[string]time.Duration{}
+	for _, s := range sessions {
+		if s.isWork() && s.Posture != "" {
+			totals[s.Posture] += s.Duration
+		}
+	}
+	return totals
+}
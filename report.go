@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// focusReport totals work sessions between from and to (inclusive). Days
+// carrying one of the excluded labels are left out; with byLabel the
+// totals are grouped by day label instead of listed per day.
+func focusReport(index sessionIndex, days map[string]dayEntry, from time.Time, to time.Time,
+	exclude []string, byLabel bool) string {
+	totals := map[string]time.Duration{}
+	standingTotals := map[string]time.Duration{}
+	counts := map[string]int{}
+
+	for day := from; day.Format(time.DateOnly) <= to.Format(time.DateOnly); day = day.AddDate(0, 0, 1) {
+		key := day.Format(time.DateOnly)
+		total, ok := index.days[key]
+		if !ok || total.Pomodoros == 0 {
+			continue
+		}
+
+		entry := days[key]
+		if entry.hasLabel(exclude) {
+			continue
+		}
+
+		groups := []string{key}
+		if byLabel {
+			groups = entry.Labels
+			if len(groups) == 0 {
+				groups = []string{"unlabelled"}
+			}
+		}
+		for _, group := range groups {
+			totals[group] += total.Focus
+			counts[group] += total.Pomodoros
+			standingTotals[group] += total.Standing
+		}
+	}
+
+	if len(totals) == 0 {
+		return "No completed sessions in this period.\n"
+	}
+
+	groups := []string{}
+	for group := range totals {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	result := fmt.Sprintf("Focus from %s to %s:\n", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	for _, group := range groups {
+		line := fmt.Sprintf("%-12s %3d pomodoros  %s", group, counts[group], formatMinutes(totals[group]))
+		if standingTotals[group] > 0 {
+			line += fmt.Sprintf("  (standing %s)", formatMinutes(standingTotals[group]))
+		}
+		if !byLabel && len(days[group].Labels) > 0 {
+			line += fmt.Sprintf("  [%s]", strings.Join(days[group].Labels, ", "))
+		}
+		result += line + "\n"
+	}
+	return result
+}
+
+func runReport(args []string) error {
+	flags := newFlagSet("report")
+	from := flags.String("from", "", "first day as YYYY-MM-DD (default 7 days ago)")
+	to := flags.String("to", "", "last day as YYYY-MM-DD (default today)")
+	exclude := flags.String("exclude", "", "comma separated day labels to leave out, e.g. sick,travel")
+	byLabel := flags.Bool("by-label", false, "group totals by day label")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -6)
+	for flag, date := range map[*string]*time.Time{from: &start, to: &end} {
+		if *flag == "" {
+			continue
+		}
+		parsed, err := time.ParseInLocation(time.DateOnly, *flag, time.Local)
+		if err != nil {
+			return fmt.Errorf("Invalid date format")
+		}
+		*date = parsed
+	}
+	if start.Format(time.DateOnly) > end.Format(time.DateOnly) {
+		return fmt.Errorf("Invalid date range: -from is after -to")
+	}
+
+	excluded := []string{}
+	if *exclude != "" {
+		excluded = strings.Split(*exclude, ",")
+	}
+
+	sessions, err := loadSessions()
+	if err != nil {
+		return err
+	}
+
+	days, err := loadDays()
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(focusReport(newSessionIndex(sessions), days, start, end, excluded, *byLabel))
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const holidaysFile = "holidays.json"
+
+var defaultRestDays = []string{"saturday", "sunday"}
+
+// restCalendar knows the days that neither break a streak nor count
+// against a goal: rest weekdays, vacations and holidays.
+type restCalendar struct {
+	weekdays  map[time.Weekday]bool
+	vacations []vacation
+	holidays  map[string]string
+}
+
+type vacation struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func newRestCalendar(cfg config, holidays map[string]string) restCalendar {
+	restDays := cfg.RestDays
+	if restDays == nil {
+		restDays = defaultRestDays
+	}
+
+	weekdays := map[time.Weekday]bool{}
+	for _, name := range restDays {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), name) {
+				weekdays[day] = true
+			}
+		}
+	}
+
+	return restCalendar{weekdays: weekdays, vacations: cfg.Vacations, holidays: holidays}
+}
+
+// restReason returns why date is a rest day, or "" for a working day.
+func (r restCalendar) restReason(date time.Time) string {
+	key := date.Format(time.DateOnly)
+	if name, ok := r.holidays[key]; ok {
+		return name
+	}
+	for _, v := range r.vacations {
+		if key >= v.From && key <= v.To {
+			return "vacation"
+		}
+	}
+	if r.weekdays[date.Weekday()] {
+		return date.Weekday().String()
+	}
+	return ""
+}
+
+func (r restCalendar) isRestDay(date time.Time) bool {
+	return r.restReason(date) != ""
+}
+
+// streak counts the working days in a row that met the daily goal, ending
+// today. Rest days are skipped, and today only counts once the goal is met.
+func streak(index sessionIndex, rest restCalendar, goal int, today time.Time) int {
+	goal = max(goal, 1)
+
+	days := 0
+	for day := today; ; day = day.AddDate(0, 0, -1) {
+		if rest.isRestDay(day) {
+			if today.Sub(day) > 366*24*time.Hour {
+				return days
+			}
+			continue
+		}
+
+		if index.pomodoros(day) >= goal {
+			days++
+			continue
+		}
+		if day.Equal(today) {
+			continue
+		}
+		return days
+	}
+}
+
+// goalLine summarizes the streak and today's progress for the idle view.
+func goalLine(index sessionIndex, rest restCalendar, goal int) string {
+	today := time.Now()
+	done := index.pomodoros(today)
+
+	line := fmt.Sprintf("🔥 Streak: %d days", streak(index, rest, goal, today))
+	if reason := rest.restReason(today); reason != "" {
+		return line + fmt.Sprintf(" · Today is a rest day (%s)", reason)
+	}
+	if goal > 0 {
+		return line + fmt.Sprintf(" · Today: %d/%d pomodoros", done, goal)
+	}
+	return line + fmt.Sprintf(" · Today: %d pomodoros", done)
+}
+
+func loadHolidays() (map[string]string, error) {
+	holidays := map[string]string{}
+	err := readJSON(holidaysFile, &holidays)
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	return holidays, nil
+}
+
+func saveHolidays(holidays map[string]string) error {
+	data, err := json.MarshalIndent(holidays, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error marshal data: %v", err.Error())
+	}
+
+	err = writeFile(holidaysFile, data)
+	if err != nil {
+		return fmt.Errorf("Error writing to file: %v", err.Error())
+	}
+
+	return nil
+}
+
+// icsUnescaper undoes the escaping of RFC 5545 TEXT values.
+var icsUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// parseICS reads all-day events from an iCalendar file into a map from
+// date to event summary. Multi-day events cover every day up to DTEND.
+func parseICS(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Long lines are folded onto continuation lines starting with a space.
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	holidays := map[string]string{}
+	var start, end time.Time
+	summary := ""
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+
+		switch name {
+		case "BEGIN":
+			start, end, summary = time.Time{}, time.Time{}, ""
+		case "DTSTART":
+			start, _ = time.ParseInLocation("20060102", value[:min(len(value), 8)], time.Local)
+		case "DTEND":
+			end, _ = time.ParseInLocation("20060102", value[:min(len(value), 8)], time.Local)
+		case "SUMMARY":
+			// Holidays are shown on one line.
+			summary = strings.ReplaceAll(icsUnescaper.Replace(value), "\n", " ")
+		case "END":
+			if value != "VEVENT" || start.IsZero() {
+				continue
+			}
+			if !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+				holidays[day.Format(time.DateOnly)] = summary
+			}
+		}
+	}
+
+	return holidays, nil
+}
+
+func runHolidays(args []string) error {
+	if len(args) == 2 && args[0] == "import" {
+		imported, err := parseICS(args[1])
+		if err != nil {
+			return fmt.Errorf("Error reading calendar: %v", err)
+		}
+
+		holidays, err := loadHolidays()
+		if err != nil {
+			return err
+		}
+		for date, name := range imported {
+			holidays[date] = name
+		}
+		err = saveHolidays(holidays)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Imported %d holidays\n", len(imported))
+		return nil
+	}
+
+	if len(args) != 0 {
+		return fmt.Errorf("usage: pomodoro holidays [import <file.ics>]")
+	}
+
+	holidays, err := loadHolidays()
+	if err != nil {
+		return err
+	}
+
+	dates := []string{}
+	for date := range holidays {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		fmt.Printf("%s %s\n", date, holidays[date])
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseICSUnescapesSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20241225\r\n" +
+		"SUMMARY:Christmas\\, Boxing Day\\; office closed\\nsee C:\\\\hr\\N\r\n" +
+		"  for details\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if err := os.WriteFile(path, []byte(ics), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	holidays, err := parseICS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Christmas, Boxing Day; office closed see C:\hr  for details`
+	if got := holidays["2024-12-25"]; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestRestDaysCountInReports(t *testing.T) {
+	// Saturday 4 May 2024 is a rest day, Monday 6 May a holiday.
+	saturday := time.Date(2024, 5, 4, 10, 0, 0, 0, time.Local)
+	monday := time.Date(2024, 5, 6, 10, 0, 0, 0, time.Local)
+	friday := time.Date(2024, 5, 3, 10, 0, 0, 0, time.Local)
+	index := newSessionIndex([]session{
+		{StartTime: friday, Duration: 25 * time.Minute},
+		{StartTime: saturday, Duration: 25 * time.Minute, Intent: "a", Outcome: "yes"},
+		{StartTime: monday, Duration: 25 * time.Minute, Intent: "b", Outcome: "no"},
+	})
+	rest := newRestCalendar(config{}, map[string]string{"2024-05-06": "Bank holiday"})
+
+	report := focusReport(index, map[string]dayEntry{}, friday, monday, nil, false)
+	for _, day := range []string{"2024-05-03", "2024-05-04", "2024-05-06"} {
+		if !strings.Contains(report, day+" ") {
+			t.Errorf("focus report leaves out %s:\n%s", day, report)
+		}
+	}
+
+	report = intentReport(index, 2, monday)
+	if !strings.Contains(report, "Overall:  50% achieved (1 yes, 0 partly, 1 no)") {
+		t.Errorf("intent report leaves out rest days:\n%s", report)
+	}
+
+	// Rest days neither break nor extend the streak.
+	if got := streak(index, rest, 1, monday.AddDate(0, 0, 1)); got != 1 {
+		t.Errorf("streak on Tuesday = %d, want 1 for Friday", got)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var currentStepStyle = lipgloss.NewStyle().Bold(true).Underline(true).Render
+
+type sequenceStep struct {
+	SessionType string
+	Minutes     int
+}
+
+// sequenceRun steps through a list of sessions automatically.
+type sequenceRun struct {
+	id      string
+	name    string
+	steps   []sequenceStep
+	current int
+}
+
+func (r sequenceRun) active() bool {
+	return len(r.steps) > 0
+}
+
+// parseSequence reads steps such as "50w 10b 90w 30b".
+func parseSequence(spec string) ([]sequenceStep, error) {
+	steps := []sequenceStep{}
+	for _, field := range strings.Fields(spec) {
+		if len(field) < 2 {
+			return nil, fmt.Errorf("Invalid step %q", field)
+		}
+
+		sessionType := ""
+		switch field[len(field)-1] {
+		case 'w':
+			sessionType = workSession
+		case 'b':
+			sessionType = breakSession
+		default:
+			return nil, fmt.Errorf("Invalid step %q, use e.g. 50w or 10b", field)
+		}
+
+		minutes, err := strconv.Atoi(field[:len(field)-1])
+		if err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("Invalid step %q", field)
+		}
+		steps = append(steps, sequenceStep{SessionType: sessionType, Minutes: minutes})
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("Empty sequence")
+	}
+	return steps, nil
+}
+
+// startSequence starts a named sequence from the config or inline steps.
+func (m *model) startSequence(spec string) tea.Cmd {
+	name := ""
+	if configured, ok := m.config.Sequences[spec]; ok {
+		name = spec
+		spec = configured
+	}
+
+	steps, err := parseSequence(spec)
+	if err != nil {
+		m.toast(slog.LevelError, err.Error())
+		return nil
+	}
+
+	m.preset = ""
+	m.sequence = sequenceRun{
+		id:    time.Now().Format("20060102-150405"),
+		name:  name,
+		steps: steps,
+	}
+	return m.startStep(0)
+}
+
+func (m *model) startStep(index int) tea.Cmd {
+	m.sequence.current = index
+	step := m.sequence.steps[index]
+	return m.startSession(step.SessionType, step.Minutes)
+}
+
+// nextStep starts the step after the current one, ending the run after the
+// last step.
+func (m *model) nextStep() tea.Cmd {
+	if m.sequence.current+1 >= len(m.sequence.steps) {
+		m.sequence = sequenceRun{}
+		return nil
+	}
+	return m.startStep(m.sequence.current + 1)
+}
+
+// jumpToStep abandons the current step and starts the given one.
+func (m *model) jumpToStep(index int) tea.Cmd {
+	if index < 0 || index >= len(m.sequence.steps) || index == m.sequence.current {
+		return nil
+	}
+
+	sequence := m.sequence
+	cmd := m.stopSession()
+	m.sequence = sequence
+	return tea.Batch(cmd, m.startStep(index))
+}
+
+// sequenceOverview shows all steps with the current one highlighted.
+func (r sequenceRun) overview() string {
+	steps := []string{}
+	for i, step := range r.steps {
+		label := fmt.Sprintf("%d%s", step.Minutes, strings.ToLower(step.SessionType[:1]))
+		if i == r.current {
+			label = currentStepStyle(label)
+		}
+		steps = append(steps, label)
+	}
+
+	title := "Sequence"
+	if r.name != "" {
+		title += " " + r.name
+	}
+	return fmt.Sprintf("%s %d/%d: %s", title, r.current+1, len(r.steps), strings.Join(steps, " "))
+}
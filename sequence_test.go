@@ -0,0 +1,119 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []sequenceStep
+		wantErr bool
+	}{
+		{spec: "50w 10b", want: []sequenceStep{{workSession, 50}, {breakSession, 10}}},
+		{spec: "  25w\t5b 90w ", want: []sequenceStep{{workSession, 25}, {breakSession, 5}, {workSession, 90}}},
+		{spec: "", wantErr: true},
+		{spec: "   ", wantErr: true},
+		{spec: "w", wantErr: true},
+		{spec: "50", wantErr: true},
+		{spec: "50x", wantErr: true},
+		{spec: "50W", wantErr: true},
+		{spec: "0w", wantErr: true},
+		{spec: "-5b", wantErr: true},
+		{spec: "fivew", wantErr: true},
+		{spec: "25w 5", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseSequence(test.spec)
+		if (err != nil) != test.wantErr || !slices.Equal(got, test.want) {
+			t.Errorf("parseSequence(%q) = %v, %v, want %v, error %v", test.spec, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+// runningSequence starts spec past the start countdown.
+func runningSequence(t *testing.T, spec string) model {
+	t.Helper()
+	m := initialModel()
+	m.loading = false
+	m.startSequence(spec)
+	if !m.sequence.active() {
+		t.Fatalf("startSequence(%q) did not start", spec)
+	}
+	m.opening = false
+	return m
+}
+
+func TestSequenceAdvances(t *testing.T) {
+	chdirTemp(t)
+	m := runningSequence(t, "25w 5b 50w")
+
+	wantSteps := []sequenceStep{{workSession, 25}, {breakSession, 5}, {workSession, 50}}
+	for i, want := range wantSteps {
+		if m.sequence.current != i || m.sessionType != want.SessionType || m.timerDuration.Minutes() != float64(want.Minutes) {
+			t.Fatalf("step %d: current = %d, running %s for %v, want %s for %dm",
+				i+1, m.sequence.current, m.sessionType, m.timerDuration, want.SessionType, want.Minutes)
+		}
+		m.finishSession()
+		m.opening = false
+	}
+
+	if m.sequence.active() || m.inSession {
+		t.Errorf("after the last step: sequence active = %v, in session = %v, want both over", m.sequence.active(), m.inSession)
+	}
+	steps := []int{}
+	for _, s := range m.sessions {
+		steps = append(steps, s.Step)
+	}
+	if !slices.Equal(steps, []int{1, 2, 3}) {
+		t.Errorf("recorded steps = %v, want [1 2 3]", steps)
+	}
+}
+
+func TestSequenceJumps(t *testing.T) {
+	chdirTemp(t)
+	m := runningSequence(t, "25w 5b 50w 10b")
+
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{key: "n", want: 1},
+		{key: "4", want: 3},
+		{key: "4", want: 3},
+		{key: "9", want: 3},
+		{key: "1", want: 0},
+		{key: "n", want: 1},
+	}
+
+	id := m.sequence.id
+	for _, test := range tests {
+		next, _ := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(test.key)})
+		m = next.(model)
+		if m.sequence.id != id || m.sequence.current != test.want || !m.inSession {
+			t.Fatalf("after %q: step %d of sequence %q, in session = %v, want step %d of %q",
+				test.key, m.sequence.current+1, m.sequence.id, m.inSession, test.want+1, id)
+		}
+		m.opening = false
+	}
+
+	if len(m.sessions) != 0 {
+		t.Errorf("jumping recorded %d sessions, want the skipped steps dropped", len(m.sessions))
+	}
+}
+
+func TestNamedSequence(t *testing.T) {
+	chdirTemp(t)
+	m := initialModel()
+	m.config.Sequences = map[string]string{"deep": "90w 30b"}
+	m.startSequence("deep")
+
+	if m.sequence.name != "deep" || len(m.sequence.steps) != 2 || m.timerDuration.Minutes() != 90 {
+		t.Errorf("startSequence(deep) = %q with %d steps running %v, want the configured steps",
+			m.sequence.name, len(m.sequence.steps), m.timerDuration)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Finished side timers stay on screen for a while before they are removed.
+const sideTimerLinger = time.Minute
+
+// sideTimer runs next to the main timer and is never saved as a session.
+type sideTimer struct {
+	name     string
+	duration time.Duration
+	deadline time.Time
+	done     bool
+}
+
+// sideTickMsg is dropped when its id is outdated, like tickMsg.
+type sideTickMsg struct {
+	now time.Time
+	id  int
+}
+
+// handleSideTimer adds a timer for "t <name> <duration>" or removes one
+// for "t <name>".
+func (m *model) handleSideTimer(command string) tea.Cmd {
+	fields := strings.Fields(command)
+	switch len(fields) {
+	case 2:
+		for i, timer := range m.timers {
+			if timer.name == fields[1] {
+				m.timers = append(m.timers[:i:i], m.timers[i+1:]...)
+				return nil
+			}
+		}
+		m.toast(slog.LevelWarn, fmt.Sprintf("No timer named %s", fields[1]))
+		return nil
+	case 3:
+	default:
+		m.toast(slog.LevelWarn, "Invalid command, use t <name> <duration>")
+		return nil
+	}
+
+	duration, err := time.ParseDuration(fields[2])
+	if err != nil {
+		minutes, convErr := strconv.Atoi(fields[2])
+		if convErr != nil {
+			m.toast(slog.LevelWarn, "Invalid duration, use e.g. 10m or 1h30m")
+			return nil
+		}
+		duration = time.Duration(minutes) * time.Minute
+	}
+	if duration <= 0 {
+		m.toast(slog.LevelWarn, "Invalid duration, use e.g. 10m or 1h30m")
+		return nil
+	}
+
+	timers := []sideTimer{}
+	for _, timer := range m.timers {
+		if timer.name != fields[1] {
+			timers = append(timers, timer)
+		}
+	}
+	m.timers = append(timers, sideTimer{
+		name:     fields[1],
+		duration: duration,
+		deadline: time.Now().Add(duration),
+	})
+
+	if m.sideTicking {
+		return nil
+	}
+	m.sideTicking = true
+	m.sideTickID++
+	return m.sideTickCmd()
+}
+
+// sideTick fires a notification for each timer that ran out, or rings the
+// terminal bell without a notify or sound command, and keeps ticking while
+// any timer is left.
+func (m *model) sideTick(now time.Time) tea.Cmd {
+	cmds := []tea.Cmd{}
+	timers := []sideTimer{}
+	for _, timer := range m.timers {
+		if !timer.done && !now.Before(timer.deadline) {
+			timer.done = true
+			cmds = append(cmds,
+				notifyCmd(m.config.Notify, "Timer "+timer.name, fmt.Sprintf("%s is up", timer.duration)),
+				soundCmd(m.config.Sound),
+			)
+			if len(m.config.Notify) == 0 && len(m.config.Sound) == 0 && !m.headless {
+				cmds = append(cmds, bellCmd(terminal))
+			}
+		}
+		if timer.done && now.Sub(timer.deadline) > sideTimerLinger {
+			continue
+		}
+		timers = append(timers, timer)
+	}
+	m.timers = timers
+
+	if len(m.timers) == 0 {
+		m.sideTicking = false
+		return tea.Batch(cmds...)
+	}
+	return tea.Batch(append(cmds, m.sideTickCmd())...)
+}
+
+func (m model) sideTimersView() string {
+	if len(m.timers) == 0 {
+		return ""
+	}
+
+	items := []string{}
+	for _, timer := range m.timers {
+		if timer.done {
+			items = append(items, markStyle(timer.name+" done"))
+			continue
+		}
+		left := time.Until(timer.deadline).Round(time.Second)
+		items = append(items, fmt.Sprintf("%s %s", timer.name, left))
+	}
+	return "⏱ " + strings.Join(items, "  ·  ")
+}
+
+// sideTickDelay is how long after now the side timers next wake.
+func (m model) sideTickDelay(now time.Time) time.Duration {
+	if !m.blurred {
+		return time.Second
+	}
+
+	delay := blurredTick
+	for _, timer := range m.timers {
+		wake := timer.deadline
+		if timer.done {
+			wake = wake.Add(sideTimerLinger)
+		}
+		delay = max(min(delay, wake.Sub(now)), 0)
+	}
+	return delay
+}
+
+// sideTickCmd ticks every second to count down on screen, or while
+// blurred only when the next timer runs out or is removed.
+func (m model) sideTickCmd() tea.Cmd {
+	id := m.sideTickID
+	return tea.Tick(m.sideTickDelay(time.Now())+tickSlack, func(t time.Time) tea.Msg {
+		return sideTickMsg{now: t, id: id}
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runCmd runs cmd and every command of a batch it returns.
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, cmd := range batch {
+			runCmd(cmd)
+		}
+	}
+}
+
+func TestSideTimerExpiryRingsBell(t *testing.T) {
+	chdirTemp(t)
+	output, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer output.Close()
+	saved := terminal
+	terminal = &ttyWriter{File: output}
+	t.Cleanup(func() { terminal = saved })
+
+	tests := []struct {
+		name     string
+		notify   []string
+		sound    []string
+		headless bool
+		want     string
+	}{
+		{name: "no alert configured", want: "\a"},
+		{name: "notify", notify: []string{"true"}},
+		{name: "sound", sound: []string{"true"}},
+		{name: "headless", headless: true},
+	}
+
+	for _, test := range tests {
+		if err := output.Truncate(0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := output.Seek(0, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		m := initialModel()
+		m.config.Notify = test.notify
+		m.config.Sound = test.sound
+		m.headless = test.headless
+		// Long expired, so it is also removed and no further tick waits.
+		now := time.Now()
+		m.timers = []sideTimer{{name: "tea", duration: time.Minute, deadline: now.Add(-2 * sideTimerLinger)}}
+		runCmd(m.sideTick(now))
+
+		got, err := os.ReadFile(output.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != test.want {
+			t.Errorf("%s: wrote %q to the terminal, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const defaultStandupTemplate = `Yesterday ({{.Yesterday.Format "Mon Jan 2"}}):
+{{- range .Projects}}
+- {{if .Name}}{{.Name}}{{else}}No project{{end}}: {{minutes .Focus}}
+{{- range .Tasks}}
+  - {{.}}
+{{- end}}
+{{- else}}
+- No focus sessions
+{{- end}}
+
+Today:
+{{- range .Today}}
+- {{.}}
+{{- else}}
+- Nothing planned yet
+{{- end}}
+
+Blockers:
+{{- range .Blockers}}
+- {{.}}
+{{- else}}
+- None
+{{- end}}
+`
+
+type standupProject struct {
+	Name  string
+	Focus time.Duration
+	Tasks []string
+}
+
+type standupData struct {
+	Yesterday time.Time
+	Today     []string
+	Blockers  []string
+	Projects  []standupProject
+}
+
+// previousWorkday returns the last working day before date, looking back
+// at most a year.
+func previousWorkday(date time.Time, rest restCalendar) time.Time {
+	day := date.AddDate(0, 0, -1)
+	for rest.isRestDay(day) && date.Sub(day) < 366*24*time.Hour {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+func standupReport(index sessionIndex, plan []planItem, rest restCalendar, today time.Time) standupData {
+	yesterday := previousWorkday(today, rest)
+	data := standupData{
+		Yesterday: yesterday,
+		Today:     planFor(plan, today, false),
+		Blockers:  append(planFor(plan, yesterday, true), planFor(plan, today, true)...),
+	}
+
+	projects := map[string]*standupProject{}
+	for _, s := range index.day(yesterday) {
+		if !s.isWork() {
+			continue
+		}
+
+		project, ok := projects[s.Project]
+		if !ok {
+			project = &standupProject{Name: s.Project}
+			projects[s.Project] = project
+		}
+		project.Focus += s.Duration
+
+		if s.Intent != "" {
+			task := s.Intent
+			if s.Outcome != "" && s.Outcome != "yes" {
+				task += fmt.Sprintf(" (%s)", s.Outcome)
+			}
+			project.Tasks = append(project.Tasks, task)
+		}
+	}
+
+	for _, project := range projects {
+		data.Projects = append(data.Projects, *project)
+	}
+	sort.Slice(data.Projects, func(i, j int) bool {
+		return data.Projects[i].Focus > data.Projects[j].Focus
+	})
+
+	return data
+}
+
+func renderStandup(format string, data standupData) (string, error) {
+	if format == "" {
+		format = defaultStandupTemplate
+	}
+
+	tmpl, err := template.New("standup").Funcs(template.FuncMap{
+		"minutes": formatMinutes,
+	}).Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing standup template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("Error rendering standup: %v", err)
+	}
+	return buf.String(), nil
+}
+
+func runStandup(args []string) error {
+	flags := newFlagSet("standup")
+	copyText := flags.Bool("copy", false, "copy the summary to the clipboard")
+	date := flags.String("date", "", "day of the standup as YYYY-MM-DD (default today)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	today := time.Now()
+	if *date != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, *date, time.Local)
+		if err != nil {
+			return fmt.Errorf("Invalid date format")
+		}
+		today = parsed
+	}
+
+	sessions, err := loadSessions()
+	if err != nil {
+		return err
+	}
+
+	plan, err := loadPlan()
+	if err != nil {
+		return err
+	}
+	holidays, err := loadHolidays()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	rest := newRestCalendar(cfg, holidays)
+	text, err := renderStandup(cfg.Standup.Template, standupReport(newSessionIndex(sessions), plan, rest, today))
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(text)
+	if *copyText {
+		return copyToClipboard(strings.TrimSpace(text), os.Stderr)
+	}
+	return nil
+}
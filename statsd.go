@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultStatsdAddress = "127.0.0.1:8125"
+	defaultStatsdPrefix  = "pomodoro"
+	// statsdGaugeEvery spaces out the remaining time gauge.
+	statsdGaugeEvery = 10 * time.Second
+)
+
+type statsdConfig struct {
+	Enabled    bool    `json:"enabled"`
+	Address    string  `json:"address"`     // host:port, 127.0.0.1:8125 by default
+	Format     string  `json:"format"`      // "statsd" (default) or "dogstatsd"
+	Prefix     string  `json:"prefix"`      // "pomodoro" by default
+	SampleRate float64 `json:"sample_rate"` // share of counters and timings sent, 1 by default
+}
+
+type metric struct {
+	name  string
+	value string
+	kind  string // "c", "g" or "ms"
+}
+
+// eventMetrics are the metrics an event updates: completions and their
+// focus time, pauses, and the remaining time when a session starts or ends.
+func eventMetrics(event lifecycleEvent) []metric {
+	switch event.Kind {
+	case eventStart:
+		return []metric{{"remaining_seconds", strconv.Itoa(int(event.Duration.Seconds())), "g"}}
+	case eventComplete:
+		metrics := []metric{{"completed", "1", "c"}, {"remaining_seconds", "0", "g"}}
+		if event.SessionType == workSession {
+			metrics = append(metrics, metric{"focus_duration", strconv.FormatInt(event.Duration.Milliseconds(), 10), "ms"})
+		}
+		return metrics
+	case eventAbandon:
+		return []metric{{"remaining_seconds", "0", "g"}}
+	case eventPause:
+		return []metric{{"pauses", "1", "c"}}
+	}
+	return nil
+}
+
+func statsdCmd(cfg statsdConfig, event lifecycleEvent) tea.Cmd {
+	return cfg.send(eventMetrics(event), event)
+}
+
+// remainingGaugeCmd reports the time left once statsdGaugeEvery has passed
+// since it was last sent. Ticks don't land on whole seconds while the
+// terminal is blurred, so the time left can't be used to space it out.
+func (m *model) remainingGaugeCmd(now time.Time) tea.Cmd {
+	if !m.config.Statsd.Enabled || m.remainingTime <= 0 {
+		return nil
+	}
+	if !m.gaugeSentAt.IsZero() && now.Sub(m.gaugeSentAt) < statsdGaugeEvery {
+		return nil
+	}
+	m.gaugeSentAt = now
+
+	metrics := []metric{{"remaining_seconds", strconv.Itoa(int(m.remainingTime.Seconds())), "g"}}
+	return m.config.Statsd.send(metrics, m.event(""))
+}
+
+func (cfg statsdConfig) send(metrics []metric, event lifecycleEvent) tea.Cmd {
+	if !cfg.Enabled || len(metrics) == 0 {
+		return nil
+	}
+
+	packet := cfg.format(metrics, event, rand.Float64)
+	if packet == "" {
+		return nil
+	}
+
+	address := cfg.Address
+	if address == "" {
+		address = defaultStatsdAddress
+	}
+
+	return func() tea.Msg {
+		err := traced("statsd", func() error {
+			conn, err := net.Dial("udp", address)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			_, err = conn.Write([]byte(packet))
+			return err
+		})
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error sending metrics: %v", err)}
+		}
+		return nil
+	}
+}
+
+// format renders metrics one per line, e.g. "pomodoro.completed:1|c|@0.5"
+// or with DogStatsD "pomodoro.completed:1|c|#type:work,project:api,tag:review".
+// Counters and timings left out by sampling are dropped.
+func (cfg statsdConfig) format(metrics []metric, event lifecycleEvent, random func() float64) string {
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = defaultStatsdPrefix
+	}
+	rate := cfg.SampleRate
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+
+	tags := []string{}
+	if cfg.Format == "dogstatsd" {
+		tags = append(tags, "type:"+statsdTag(event.SessionType))
+		if event.Project != "" {
+			tags = append(tags, "project:"+statsdTag(event.Project))
+		}
+		for _, tag := range event.Tags {
+			tags = append(tags, "tag:"+statsdTag(tag))
+		}
+	}
+
+	lines := []string{}
+	for _, metric := range metrics {
+		line := fmt.Sprintf("%s.%s:%s|%s", prefix, metric.name, metric.value, metric.kind)
+		if metric.kind != "g" && rate < 1 {
+			if random() >= rate {
+				continue
+			}
+			line += "|@" + strconv.FormatFloat(rate, 'f', -1, 64)
+		}
+		if len(tags) > 0 {
+			line += "|#" + strings.Join(tags, ",")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statsdTag lowercases a tag value and replaces what DogStatsD reserves.
+func statsdTag(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '|', '#', ' ':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(value))
+}
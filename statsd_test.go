@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+var statsdTestEvent = lifecycleEvent{
+	Kind:        eventComplete,
+	SessionType: workSession,
+	Project:     "API",
+	Tags:        []string{"review", "pair programming"},
+	Duration:    25 * time.Minute,
+}
+
+// sequence returns the given values one call at a time.
+func sequence(values ...float64) func() float64 {
+	return func() float64 {
+		value := values[0]
+		values = values[1:]
+		return value
+	}
+}
+
+func TestStatsdFormat(t *testing.T) {
+	got := statsdConfig{}.format(eventMetrics(statsdTestEvent), statsdTestEvent, nil)
+	want := "pomodoro.completed:1|c\n" +
+		"pomodoro.remaining_seconds:0|g\n" +
+		"pomodoro.focus_duration:1500000|ms"
+	if got != want {
+		t.Errorf("format() = %q, want %q", got, want)
+	}
+}
+
+func TestStatsdFormatDogStatsDTags(t *testing.T) {
+	cfg := statsdConfig{Format: "dogstatsd", Prefix: "focus"}
+	metrics := []metric{{"pauses", "1", "c"}}
+
+	got := cfg.format(metrics, statsdTestEvent, nil)
+	want := "focus.pauses:1|c|#type:work,project:api,tag:review,tag:pair_programming"
+	if got != want {
+		t.Errorf("format() = %q, want %q", got, want)
+	}
+
+	got = cfg.format(metrics, lifecycleEvent{SessionType: breakSession}, nil)
+	if want := "focus.pauses:1|c|#type:break"; got != want {
+		t.Errorf("format() without project or tags = %q, want %q", got, want)
+	}
+}
+
+func TestStatsdFormatSampling(t *testing.T) {
+	cfg := statsdConfig{SampleRate: 0.25}
+	metrics := eventMetrics(statsdTestEvent)
+
+	// The counter is kept, the timing is left out and gauges are never sampled.
+	got := cfg.format(metrics, statsdTestEvent, sequence(0.1, 0.3))
+	want := "pomodoro.completed:1|c|@0.25\npomodoro.remaining_seconds:0|g"
+	if got != want {
+		t.Errorf("format() = %q, want %q", got, want)
+	}
+
+	got = cfg.format([]metric{{"pauses", "1", "c"}}, statsdTestEvent, sequence(0.25))
+	if got != "" {
+		t.Errorf("format() with every metric sampled out = %q, want nothing", got)
+	}
+
+	// Rates outside (0, 1] send everything.
+	for _, rate := range []float64{0, 1, 2} {
+		cfg := statsdConfig{SampleRate: rate}
+		if got := cfg.format(metrics, statsdTestEvent, nil); strings.Contains(got, "@") {
+			t.Errorf("format() with rate %v = %q, want no sampling", rate, got)
+		}
+	}
+}
+
+func TestStatsdSendUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	cfg := statsdConfig{Enabled: true, Address: conn.LocalAddr().String(), Format: "dogstatsd"}
+	cmd := statsdCmd(cfg, statsdTestEvent)
+	if cmd == nil {
+		t.Fatal("statsdCmd() sends nothing")
+	}
+	if msg, ok := cmd().(integrationErrMsg); ok {
+		t.Fatal(msg.err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1500)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Split(string(buf[:n]), "\n")
+	if len(got) != 3 || !strings.HasPrefix(got[0], "pomodoro.completed:1|c|#type:work,project:api") {
+		t.Errorf("received %q, want the three metrics of a completed work session", got)
+	}
+}
+
+func TestStatsdDisabled(t *testing.T) {
+	if cmd := statsdCmd(statsdConfig{}, statsdTestEvent); cmd != nil {
+		t.Error("statsdCmd() sends while disabled")
+	}
+	if cmd := statsdCmd(statsdConfig{Enabled: true}, lifecycleEvent{Kind: eventMark}); cmd != nil {
+		t.Error("statsdCmd() sends for an event without metrics")
+	}
+}
+
+func TestRemainingGauge(t *testing.T) {
+	m := model{config: config{Statsd: statsdConfig{Enabled: true}}, sessionType: workSession}
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+
+	// Blurred ticks land at odd offsets, so the time left is never a
+	// multiple of the gauge interval.
+	for _, test := range []struct {
+		elapsed   time.Duration
+		remaining time.Duration
+		sends     bool
+	}{
+		{0, 1499300 * time.Millisecond, true},
+		{4700 * time.Millisecond, 1494600 * time.Millisecond, false},
+		{9900 * time.Millisecond, 1489400 * time.Millisecond, false},
+		{10100 * time.Millisecond, 1489200 * time.Millisecond, true},
+		{19900 * time.Millisecond, 1479400 * time.Millisecond, false},
+		{20200 * time.Millisecond, 1479100 * time.Millisecond, true},
+		{40000 * time.Millisecond, 0, false},
+	} {
+		m.remainingTime = test.remaining
+		if sends := m.remainingGaugeCmd(start.Add(test.elapsed)) != nil; sends != test.sends {
+			t.Errorf("remainingGaugeCmd() after %s sends = %v, want %v", test.elapsed, sends, test.sends)
+		}
+	}
+
+	m.config.Statsd.Enabled = false
+	m.gaugeSentAt = time.Time{}
+	m.remainingTime = time.Minute
+	if m.remainingGaugeCmd(start) != nil {
+		t.Error("remainingGaugeCmd() sends with StatsD disabled")
+	}
+}
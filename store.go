@@ -0,0 +1,321 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	sessionsFile   = "db.json"
+	maxSaveRetry   = time.Minute
+	savedNoticeFor = 3 * time.Second
+)
+
+// storeLoadedMsg carries everything read from disk at startup.
+type storeLoadedMsg struct {
+	sessions []session
+	days     map[string]dayEntry
+	plan     []planItem
+	holidays map[string]string
+	errs     map[string]error // by file
+}
+
+type retryLoadMsg struct{}
+
+type savedMsg struct {
+	file string
+	err  error
+}
+
+type retrySaveMsg struct{ file string }
+
+// saveState serializes the writes of one file. Only one write runs at a
+// time; changes made meanwhile are written by a follow-up write so the
+// latest data always lands last.
+type saveState struct {
+	saving  bool
+	pending bool
+	blocked bool // the file on disk could not be read and must not be overwritten
+	err     error
+	retryIn time.Duration
+	savedAt time.Time
+}
+
+func loadStoreCmd() tea.Msg {
+	started := time.Now()
+	msg := storeLoadedMsg{errs: map[string]error{}}
+
+	var err error
+	msg.sessions, err = loadSessions()
+	if err != nil {
+		msg.errs[sessionsFile] = err
+	}
+	msg.days, err = loadDays()
+	if err != nil {
+		msg.errs[daysFile] = err
+	}
+	msg.plan, err = loadPlan()
+	if err != nil {
+		msg.errs[planFile] = err
+	}
+	msg.holidays, err = loadHolidays()
+	if err != nil {
+		msg.errs[holidaysFile] = err
+	}
+	logger.Debug("store loaded", "sessions", len(msg.sessions), "days", len(msg.days),
+		"plan", len(msg.plan), "holidays", len(msg.holidays), "duration", time.Since(started))
+	return msg
+}
+
+// storeLoaded merges the loaded data with anything recorded while loading
+// and writes what was held back meanwhile. Files that could not be read are
+// read again later; nothing is saved until then.
+func (m *model) storeLoaded(msg storeLoadedMsg) tea.Cmd {
+	for _, err := range msg.errs {
+		if errors.As(err, new(corruptFileError)) {
+			continue
+		}
+
+		m.loadErr = err
+		m.loadRetryIn = min(max(2*m.loadRetryIn, time.Second), maxSaveRetry)
+		m.toast(slog.LevelError, fmt.Sprintf("%v, retrying in %s", err, m.loadRetryIn))
+		return tea.Tick(m.loadRetryIn, func(time.Time) tea.Msg {
+			return retryLoadMsg{}
+		})
+	}
+	for file, err := range msg.errs {
+		m.unreadable(file, err)
+	}
+	m.loadErr = nil
+	m.loadRetryIn = 0
+
+	m.sessions = append(msg.sessions, m.sessions...)
+	m.index = newSessionIndex(m.sessions)
+	for date, entry := range m.days {
+		msg.days[date] = entry
+	}
+	m.days = msg.days
+	m.plan = append(msg.plan, m.plan...)
+	m.rest.holidays = msg.holidays
+	m.loading = false
+
+	if m.posture == "" {
+		m.posture = lastPosture(m.sessions)
+	}
+
+	cmds := []tea.Cmd{}
+	for file, state := range m.saves {
+		if state.pending && !state.blocked {
+			state.pending = false
+			state.saving = true
+			cmds = append(cmds, m.saveCmd(file))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// unreadable moves a corrupt file aside so new data can be saved in its
+// place. Files that cannot be moved are never overwritten.
+func (m *model) unreadable(file string, err error) {
+	aside, moveErr := moveAside(file)
+	if moveErr != nil {
+		m.saveState(file).blocked = true
+		m.toast(slog.LevelError, fmt.Sprintf("%v; could not move it aside: %v; not saving until it is fixed", err, moveErr))
+		return
+	}
+
+	logger.Warn("store moved corrupt file aside", "file", file, "to", aside)
+	m.toast(slog.LevelError, fmt.Sprintf("%v; moved it to %s", err, aside))
+}
+
+func (m *model) saveState(file string) *saveState {
+	state, ok := m.saves[file]
+	if !ok {
+		state = &saveState{}
+		m.saves[file] = state
+	}
+	return state
+}
+
+// queueSave writes file in the background after the current write of it
+// finishes.
+func (m *model) queueSave(file string) tea.Cmd {
+	state := m.saveState(file)
+
+	// Writing before the load finished would drop what is on disk.
+	if state.saving || state.blocked || m.loading {
+		state.pending = true
+		return nil
+	}
+
+	state.saving = true
+	return m.saveCmd(file)
+}
+
+// saveCmd writes a snapshot of the data behind file.
+func (m model) saveCmd(file string) tea.Cmd {
+	var save func() error
+	switch file {
+	case sessionsFile:
+		sessions := slices.Clone(m.sessions)
+		save = func() error { return saveSessions(sessions) }
+	case daysFile:
+		days := maps.Clone(m.days)
+		save = func() error { return saveDays(days) }
+	case planFile:
+		plan := slices.Clone(m.plan)
+		save = func() error { return savePlan(plan) }
+	default:
+		return nil
+	}
+
+	seq := saveSeq.Add(1)
+	return func() tea.Msg {
+		started := time.Now()
+		err := writeInOrder(file, seq, save)
+		if err != nil {
+			logger.Error("store save failed", "file", file, "duration", time.Since(started), "err", err)
+		} else {
+			logger.Debug("store saved", "file", file, "duration", time.Since(started))
+		}
+		return savedMsg{file: file, err: err}
+	}
+}
+
+// saveSeq numbers snapshots so a write started before another one never
+// lands after it, e.g. a background save that is still running on quit.
+var saveSeq atomic.Uint64
+
+var written = struct {
+	sync.Mutex
+	seq map[string]uint64
+}{seq: map[string]uint64{}}
+
+// writeInOrder runs one write at a time and skips snapshots older than the
+// one already on disk.
+func writeInOrder(file string, seq uint64, save func() error) error {
+	written.Lock()
+	defer written.Unlock()
+
+	if seq < written.seq[file] {
+		return nil
+	}
+
+	err := save()
+	if err != nil {
+		return err
+	}
+	written.seq[file] = seq
+	return nil
+}
+
+func (m *model) saved(msg savedMsg) tea.Cmd {
+	state := m.saves[msg.file]
+	if state == nil {
+		return nil
+	}
+
+	if msg.err != nil {
+		state.err = msg.err
+		state.retryIn = min(max(2*state.retryIn, time.Second), maxSaveRetry)
+		m.toast(slog.LevelError, fmt.Sprintf("%v, retrying in %s", msg.err, state.retryIn))
+		return tea.Tick(state.retryIn, func(time.Time) tea.Msg {
+			return retrySaveMsg{file: msg.file}
+		})
+	}
+
+	if state.err != nil {
+		m.toast(slog.LevelInfo, fmt.Sprintf("Saved %s", msg.file))
+	}
+	state.err = nil
+	state.retryIn = 0
+	state.savedAt = time.Now()
+	if state.pending {
+		state.pending = false
+		return m.saveCmd(msg.file)
+	}
+	state.saving = false
+	return nil
+}
+
+func (m *model) retrySave(msg retrySaveMsg) tea.Cmd {
+	state := m.saves[msg.file]
+	if state == nil {
+		return nil
+	}
+
+	state.pending = false
+	return m.saveCmd(msg.file)
+}
+
+// flush synchronously writes whatever is still unsaved, for use on exit.
+// Writes still running in the background are waited for.
+func (m model) flush() error {
+	if m.loading {
+		m.storeLoaded(loadStoreCmd().(storeLoadedMsg))
+		if m.loading {
+			return fmt.Errorf("Not saved: %v", m.loadErr)
+		}
+	}
+
+	var firstErr error
+	for file, state := range m.saves {
+		if state.blocked || !state.saving && !state.pending && state.err == nil {
+			continue
+		}
+
+		msg, _ := m.saveCmd(file)().(savedMsg)
+		if msg.err != nil && firstErr == nil {
+			firstErr = msg.err
+		}
+	}
+	return firstErr
+}
+
+// saveStatusView tells whether data is being written or failed to be.
+func (m model) saveStatusView() string {
+	if m.loading && m.loadErr != nil {
+		return overrunStyle("⚠ " + m.loadErr.Error() + ", retrying in " + m.loadRetryIn.String())
+	}
+	if m.loading {
+		return helpStyle("Loading sessions…")
+	}
+
+	// Files are checked in name order so the same failure is shown until it
+	// clears.
+	files := []string{}
+	for file := range m.saves {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+
+	saving := false
+	recent := false
+	for _, file := range files {
+		state := m.saves[file]
+		if state.blocked {
+			return overrunStyle("⚠ " + file + " is unreadable, not saving")
+		}
+		if state.err != nil {
+			return overrunStyle("⚠ " + state.err.Error() + ", retrying in " + state.retryIn.String())
+		}
+		saving = saving || state.saving
+		recent = recent || time.Since(state.savedAt) < savedNoticeFor
+	}
+
+	switch {
+	case saving:
+		return helpStyle("Saving…")
+	case recent:
+		return helpStyle("✓ Saved")
+	}
+	return ""
+}
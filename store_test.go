@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCorruptSessionsMovedAside(t *testing.T) {
+	chdirTemp(t)
+
+	corrupt := []byte(`[{"start_time": "2024-05-06T09:00:00Z",`)
+	if err := os.WriteFile(sessionsFile, corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModel()
+	m.storeLoaded(loadStoreCmd().(storeLoadedMsg))
+
+	if len(m.toasts) == 0 || m.toasts[len(m.toasts)-1].level != slog.LevelError {
+		t.Fatalf("expected an error toast, got %v", m.toasts)
+	}
+
+	aside, _ := filepath.Glob(sessionsFile + ".corrupt-*")
+	if len(aside) != 1 {
+		t.Fatalf("expected the corrupt file to be moved aside, found %v", aside)
+	}
+	kept, err := os.ReadFile(aside[0])
+	if err != nil || string(kept) != string(corrupt) {
+		t.Fatalf("moved file = %q, %v, want the original contents", kept, err)
+	}
+	if !strings.Contains(m.toasts[len(m.toasts)-1].text, aside[0]) {
+		t.Errorf("toast %q does not name %s", m.toasts[len(m.toasts)-1].text, aside[0])
+	}
+
+	// The new file is saved in place of the corrupt one.
+	m.sessions = append(m.sessions, session{StartTime: time.Now(), EndTime: time.Now(), Duration: time.Minute})
+	msg := m.queueSave(sessionsFile)().(savedMsg)
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	sessions, err := loadSessions()
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("loadSessions() = %d sessions, %v, want 1", len(sessions), err)
+	}
+}
+
+func TestUnreadableSessionsRetried(t *testing.T) {
+	chdirTemp(t)
+
+	// A directory in place of db.json cannot be read.
+	if err := os.Mkdir(sessionsFile, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModel()
+	if cmd := m.storeLoaded(loadStoreCmd().(storeLoadedMsg)); cmd == nil {
+		t.Fatal("storeLoaded() did not schedule a retry")
+	}
+	if !m.loading || len(m.toasts) == 0 || m.toasts[len(m.toasts)-1].level != slog.LevelError {
+		t.Fatalf("loading = %v, toasts = %v, want an error toast while still loading", m.loading, m.toasts)
+	}
+	if !strings.Contains(m.saveStatusView(), "retrying") {
+		t.Errorf("saveStatusView() = %q, want a retrying warning", m.saveStatusView())
+	}
+
+	m.sessions = append(m.sessions, session{StartTime: time.Now(), EndTime: time.Now(), Duration: time.Minute})
+	if cmd := m.queueSave(sessionsFile); cmd != nil {
+		t.Fatal("queueSave() saved before the load succeeded")
+	}
+	if err := m.flush(); err == nil {
+		t.Fatal("flush() saved over an unreadable file")
+	}
+	if info, err := os.Stat(sessionsFile); err != nil || !info.IsDir() {
+		t.Fatalf("db.json was replaced: %v", err)
+	}
+
+	// Once the file can be read, the held back session is saved.
+	if err := os.Remove(sessionsFile); err != nil {
+		t.Fatal(err)
+	}
+	cmd := m.storeLoaded(loadStoreCmd().(storeLoadedMsg))
+	if m.loading || cmd == nil {
+		t.Fatalf("loading = %v after a successful retry, want the pending save", m.loading)
+	}
+	if msg := cmd().(savedMsg); msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	sessions, err := loadSessions()
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("loadSessions() = %d sessions, %v, want 1", len(sessions), err)
+	}
+}
+
+func TestStaleSaveDoesNotOverwriteNewer(t *testing.T) {
+	chdirTemp(t)
+
+	m := initialModel()
+	m.storeLoaded(loadStoreCmd().(storeLoadedMsg))
+
+	m.sessions = []session{{StartTime: time.Now(), Duration: time.Minute}}
+	stale := m.queueSave(sessionsFile)
+
+	// Quitting writes the newer data while the first save is still queued.
+	m.sessions = append(m.sessions, session{StartTime: time.Now(), Duration: time.Minute})
+	if err := m.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if msg := stale().(savedMsg); msg.err != nil {
+		t.Fatal(msg.err)
+	}
+
+	sessions, err := loadSessions()
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("loadSessions() = %d sessions, %v, want the 2 saved on quit", len(sessions), err)
+	}
+	if tmp, _ := filepath.Glob("*.tmp"); len(tmp) != 0 {
+		t.Errorf("temporary files left behind: %v", tmp)
+	}
+}
+
+func TestSaveSessionsRoundTrip(t *testing.T) {
+	chdirTemp(t)
+
+	want := []session{{StartTime: time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC),
+		EndTime: time.Date(2024, 5, 6, 9, 25, 0, 0, time.UTC), Duration: 25 * time.Minute, Project: "api"}}
+	if err := saveSessions(want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("loadSessions() = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+// chdirTemp runs the test in an empty directory, where the data files live.
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestSaveStatusViewIsStable(t *testing.T) {
+	chdirTemp(t)
+
+	m := initialModel()
+	m.loading = false
+	m.saves = map[string]*saveState{
+		"plan.json":  {err: errors.New("plan.json failed"), retryIn: time.Second},
+		"days.json":  {err: errors.New("days.json failed"), retryIn: time.Second},
+		"db.json":    {saving: true},
+		"zones.json": {blocked: true},
+	}
+
+	for i := 0; i < 20; i++ {
+		if got := m.saveStatusView(); !strings.Contains(got, "days.json failed") {
+			t.Fatalf("saveStatusView() = %q, want the days.json failure", got)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// summary is a day or month of focus, ready to paste into a chat.
+type summary struct {
+	Title    string
+	Labels   []string
+	Items    []string
+	Total    string
+	Projects map[string]time.Duration
+}
+
+type copiedMsg struct{ what string }
+
+func daySummary(index sessionIndex, days map[string]dayEntry, date time.Time) summary {
+	s := summary{
+		Title:    date.Format("Mon Jan 2 2006"),
+		Labels:   days[date.Format(time.DateOnly)].Labels,
+		Projects: map[string]time.Duration{},
+	}
+
+	for _, session := range index.day(date) {
+		label := "Break"
+		if session.isWork() {
+			label = "Pomodoro"
+		}
+		item := fmt.Sprintf("%s-%s %s %s", session.StartTime.Local().Format("15:04"),
+			session.StartTime.Add(session.Duration).Local().Format("15:04"), label, formatMinutes(session.Duration))
+		if session.isWork() {
+			if session.Project != "" {
+				item += " @" + session.Project
+			}
+			if session.Intent != "" {
+				item += " · " + session.Intent
+			}
+			s.Projects[session.Project] += session.Duration
+		}
+		s.Items = append(s.Items, item)
+	}
+	total := index.totals(date)
+	s.Total = fmt.Sprintf("%d pomodoros, %s focus", total.Pomodoros, formatMinutes(total.Focus))
+	return s
+}
+
+func monthSummary(index sessionIndex, days map[string]dayEntry, month time.Time) summary {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	s := summary{Title: first.Format("January 2006"), Projects: map[string]time.Duration{}}
+
+	total := aggregate{}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		a, ok := index.days[day.Format(time.DateOnly)]
+		if !ok || a.Pomodoros == 0 {
+			continue
+		}
+
+		item := fmt.Sprintf("%s: %d pomodoros, %s", day.Format("Mon Jan 2"), a.Pomodoros, formatMinutes(a.Focus))
+		if labels := days[day.Format(time.DateOnly)].Labels; len(labels) > 0 {
+			item += fmt.Sprintf(" [%s]", strings.Join(labels, ", "))
+		}
+		s.Items = append(s.Items, item)
+
+		total.Pomodoros += a.Pomodoros
+		total.Focus += a.Focus
+		for _, session := range index.day(day) {
+			if session.isWork() {
+				s.Projects[session.Project] += session.Duration
+			}
+		}
+	}
+	s.Total = fmt.Sprintf("%d pomodoros, %s focus", total.Pomodoros, formatMinutes(total.Focus))
+	return s
+}
+
+// render formats the summary as "plain" text or "markdown".
+func (s summary) render(format string) string {
+	projects := []string{}
+	for project := range s.Projects {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	name := func(project string) string {
+		if project == "" {
+			return "No project"
+		}
+		return project
+	}
+
+	var b strings.Builder
+	if format == "markdown" {
+		fmt.Fprintf(&b, "## %s\n\n", s.Title)
+		if len(s.Labels) > 0 {
+			fmt.Fprintf(&b, "_%s_\n\n", strings.Join(s.Labels, ", "))
+		}
+		for _, item := range s.Items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+		fmt.Fprintf(&b, "\n**Total:** %s\n", s.Total)
+		if len(projects) > 0 {
+			b.WriteString("\n| Project | Focus |\n| --- | --- |\n")
+			for _, project := range projects {
+				fmt.Fprintf(&b, "| %s | %s |\n", name(project), formatMinutes(s.Projects[project]))
+			}
+		}
+		return b.String()
+	}
+
+	b.WriteString(s.Title)
+	if len(s.Labels) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(s.Labels, ", "))
+	}
+	b.WriteString("\n")
+	for _, item := range s.Items {
+		fmt.Fprintf(&b, "- %s\n", item)
+	}
+	fmt.Fprintf(&b, "Total: %s\n", s.Total)
+	for _, project := range projects {
+		fmt.Fprintf(&b, "%s: %s\n", name(project), formatMinutes(s.Projects[project]))
+	}
+	return b.String()
+}
+
+// terminal is the TUI's output. Writes to it are serialized so an OSC 52
+// sequence written from a Cmd can't land in the middle of a frame.
+var terminal = &ttyWriter{File: os.Stdout}
+
+// ttyWriter is still an *os.File, so Bubble Tea can size the terminal and
+// put it in raw mode.
+type ttyWriter struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (w *ttyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.File.Write(p)
+}
+
+// copyToClipboard uses the system clipboard, or an OSC 52 sequence written
+// to out to reach the terminal's clipboard over SSH, in tmux or when no
+// clipboard tool is installed.
+func copyToClipboard(text string, out io.Writer) error {
+	_, remote := os.LookupEnv("SSH_TTY")
+	_, tmux := os.LookupEnv("TMUX")
+	if !remote && !tmux {
+		if err := clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+
+	seq := osc52.New(text)
+	switch {
+	case tmux:
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(out)
+	if err != nil {
+		return fmt.Errorf("Error copying to clipboard: %v", err)
+	}
+	return nil
+}
+
+func copyCmd(what string, text string) tea.Cmd {
+	return func() tea.Msg {
+		err := copyToClipboard(text, terminal)
+		if err != nil {
+			return integrationErrMsg{err}
+		}
+		return copiedMsg{what: what}
+	}
+}
+
+// copySummary copies what the current history or stats view shows.
+func (m model) copySummary() tea.Cmd {
+	format := m.config.SummaryFormat
+	switch {
+	case m.showSession:
+		date := time.Now()
+		if m.printDifferentDate {
+			date = m.datePrint
+		}
+		return copyCmd("day summary", daySummary(m.index, m.days, date).render(format))
+	case m.showCalendar:
+		return copyCmd("month summary", monthSummary(m.index, m.days, m.calendarMonth).render(format))
+	case m.showIntents:
+		return copyCmd("intent report", intentReport(m.index, intentReportWeeks, time.Now()))
+	}
+	return nil
+}
+
+func runSummary(args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("summary")
+	date := flags.String("date", "", "day to summarize as YYYY-MM-DD (default today)")
+	month := flags.String("month", "", "summarize a month given as YYYY-MM instead")
+	format := flags.String("format", cfg.SummaryFormat, "plain or markdown")
+	copyText := flags.Bool("copy", false, "copy the summary to the clipboard")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "" && *format != "plain" && *format != "markdown" {
+		return fmt.Errorf("Invalid format %q, use plain or markdown", *format)
+	}
+
+	sessions, err := loadSessions()
+	if err != nil {
+		return err
+	}
+
+	days, err := loadDays()
+	if err != nil {
+		return err
+	}
+
+	index := newSessionIndex(sessions)
+
+	var s summary
+	if *month != "" {
+		parsed, err := time.ParseInLocation("2006-01", *month, time.Local)
+		if err != nil {
+			return fmt.Errorf("Invalid month format")
+		}
+		s = monthSummary(index, days, parsed)
+	} else {
+		day := time.Now()
+		if *date != "" {
+			parsed, err := time.ParseInLocation(time.DateOnly, *date, time.Local)
+			if err != nil {
+				return fmt.Errorf("Invalid date format")
+			}
+			day = parsed
+		}
+		s = daySummary(index, days, day)
+	}
+
+	text := s.render(*format)
+	fmt.Print(text)
+	if *copyText {
+		return copyToClipboard(strings.TrimSpace(text), os.Stderr)
+	}
+	return nil
+}
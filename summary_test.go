@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCopyCmdWritesToTerminal(t *testing.T) {
+	t.Setenv("SSH_TTY", "/dev/pts/1")
+	// Any TMUX, even empty, means tmux; Setenv restores it afterwards.
+	t.Setenv("TMUX", "")
+	os.Unsetenv("TMUX")
+	t.Setenv("TERM", "xterm-256color")
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	saved := terminal
+	terminal = &ttyWriter{File: file}
+	t.Cleanup(func() { terminal = saved })
+
+	if msg, ok := copyCmd("summary", "hello")().(copiedMsg); !ok || msg.what != "summary" {
+		t.Fatalf("copyCmd() = %#v, want copiedMsg", msg)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// OSC 52 with "hello" in base64.
+	if got := string(data); !strings.Contains(got, "\x1b]52;c;aGVsbG8=") {
+		t.Errorf("terminal received %q, want an OSC 52 sequence", got)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultSyslogAddress = "unix:///dev/log"
+	syslogTimeout        = 5 * time.Second
+	// syslogID names the structured data element. 32473 is the private
+	// enterprise number reserved for examples.
+	syslogID = "pomodoro@32473"
+)
+
+// Severities are notice for finished sessions and info otherwise.
+const (
+	syslogNotice = 5
+	syslogInfo   = 6
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogEvents are the lifecycle events sent to syslog.
+var syslogEvents = map[eventKind]bool{
+	eventStart:    true,
+	eventComplete: true,
+	eventAbandon:  true,
+	eventPause:    true,
+}
+
+type syslogConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Address  string `json:"address"`  // unix:///dev/log (default), udp://host:514 or tcp://host:601
+	Facility string `json:"facility"` // "user" (default), "local0" to "local7", ...
+}
+
+func syslogCmd(cfg syslogConfig, event lifecycleEvent) tea.Cmd {
+	if !cfg.Enabled || !syslogEvents[event.Kind] {
+		return nil
+	}
+
+	return func() tea.Msg {
+		err := traced("syslog", func() error { return sendSyslog(cfg, event) })
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error sending to syslog: %v", err)}
+		}
+		return nil
+	}
+}
+
+// syslogMessage formats event as an RFC 5424 message, with the session
+// fields as structured data, e.g.
+//
+//	<13>1 2024-05-06T09:25:00.000+02:00 host pomodoro 4242 work_complete [pomodoro@32473 event="work_complete" type="Work" duration_sec="1500"] Work session complete
+func syslogMessage(facility string, event lifecycleEvent) (string, error) {
+	code, ok := syslogFacilities[facility]
+	if facility == "" {
+		code, ok = syslogFacilities["user"], true
+	}
+	if !ok {
+		return "", fmt.Errorf("unknown facility %q", facility)
+	}
+
+	severity := syslogInfo
+	if event.Kind == eventComplete {
+		severity = syslogNotice
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	params := [][2]string{
+		{"event", event.name()},
+		{"type", event.SessionType},
+		{"duration_sec", strconv.Itoa(int(event.Duration.Seconds()))},
+	}
+	optional := [][2]string{
+		{"preset", event.Preset},
+		{"project", event.Project},
+		{"tags", strings.Join(event.Tags, ",")},
+		{"mark", event.Mark},
+	}
+	for _, param := range optional {
+		if param[1] != "" {
+			params = append(params, param)
+		}
+	}
+
+	data := "[" + syslogID
+	for _, param := range params {
+		data += fmt.Sprintf(" %s=\"%s\"", param[0], sdEscaper.Replace(param[1]))
+	}
+	data += "]"
+
+	return fmt.Sprintf("<%d>1 %s %s pomodoro %d %s %s %s session %s",
+		code*8+severity,
+		event.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+		hostname,
+		os.Getpid(),
+		event.name(),
+		data,
+		event.SessionType,
+		event.Kind,
+	), nil
+}
+
+// sdEscaper escapes the characters RFC 5424 reserves in parameter values.
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// sendSyslog delivers one message. Messages over TCP are framed with
+// their length as in RFC 6587; local sockets and UDP take one message per
+// datagram.
+func sendSyslog(cfg syslogConfig, event lifecycleEvent) error {
+	msg, err := syslogMessage(cfg.Facility, event)
+	if err != nil {
+		return err
+	}
+
+	address := cfg.Address
+	if address == "" {
+		address = defaultSyslogAddress
+	}
+	network, target, ok := strings.Cut(address, "://")
+	if !ok {
+		return fmt.Errorf("invalid address %q, use unix:///dev/log, udp://host:port or tcp://host:port", address)
+	}
+
+	var conn net.Conn
+	switch network {
+	case "unix":
+		// rsyslog and journald listen on datagram sockets, others on streams.
+		conn, err = net.DialTimeout("unixgram", target, syslogTimeout)
+		if err != nil {
+			conn, err = net.DialTimeout("unix", target, syslogTimeout)
+		}
+	case "udp", "tcp":
+		conn, err = net.DialTimeout(network, target, syslogTimeout)
+	default:
+		return fmt.Errorf("unsupported network %q", network)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	_, err = conn.Write([]byte(msg))
+	return err
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var syslogTestEvent = lifecycleEvent{
+	Kind:        eventComplete,
+	SessionType: workSession,
+	Project:     `api "v2"`,
+	Tags:        []string{"review", `x]y`},
+	Duration:    25 * time.Minute,
+	Time:        time.Date(2024, 5, 6, 9, 25, 0, 0, time.FixedZone("", 2*60*60)),
+}
+
+func TestSdEscaper(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`plain`, `plain`},
+		{`say "hi"`, `say \"hi\"`},
+		{`C:\work`, `C:\\work`},
+		{`[a]`, `[a\]`},
+		{`\"]`, `\\\"\]`},
+	}
+	for _, test := range tests {
+		if got := sdEscaper.Replace(test.in); got != test.want {
+			t.Errorf("sdEscaper.Replace(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestSyslogMessage(t *testing.T) {
+	msg, err := syslogMessage("local3", syslogTestEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// local3 is facility 19, a completed session has severity notice.
+	prefix := "<157>1 2024-05-06T09:25:00.000+02:00 "
+	if !strings.HasPrefix(msg, prefix) {
+		t.Errorf("message %q does not start with %q", msg, prefix)
+	}
+	data := `[pomodoro@32473 event="work_complete" type="Work" duration_sec="1500" project="api \"v2\"" tags="review,x\]y"]`
+	if !strings.Contains(msg, " pomodoro "+strconv.Itoa(os.Getpid())+" work_complete "+data+" Work session complete") {
+		t.Errorf("message %q lacks the structured data %s", msg, data)
+	}
+
+	msg, err = syslogMessage("", lifecycleEvent{Kind: eventStart, SessionType: breakSession})
+	if err != nil || !strings.HasPrefix(msg, "<14>1 ") {
+		t.Errorf("default facility message = %q, %v, want user.info", msg, err)
+	}
+
+	if _, err := syslogMessage("local9", syslogTestEvent); err == nil {
+		t.Error("syslogMessage() with an unknown facility succeeded")
+	}
+}
+
+func TestSendSyslogUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = sendSyslog(syslogConfig{Address: "unix://" + path}, syslogTestEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDatagram(t, conn)
+}
+
+func TestSendSyslogUnixStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := acceptAll(listener)
+	err = sendSyslog(syslogConfig{Address: "unix://" + path}, syslogTestEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-received; got != expectedSyslog(t) {
+		t.Errorf("received %q, want %q", got, expectedSyslog(t))
+	}
+}
+
+func TestSendSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = sendSyslog(syslogConfig{Address: "udp://" + conn.LocalAddr().String()}, syslogTestEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDatagram(t, conn)
+}
+
+func TestSendSyslogTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := acceptAll(listener)
+	err = sendSyslog(syslogConfig{Address: "tcp://" + listener.Addr().String()}, syslogTestEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 6587 octet counting: "<length> <message>".
+	frame := <-received
+	length, msg, ok := strings.Cut(frame, " ")
+	if !ok {
+		t.Fatalf("frame %q has no length prefix", frame)
+	}
+	if n, err := strconv.Atoi(length); err != nil || n != len(msg) {
+		t.Errorf("length prefix %q, message is %d bytes", length, len(msg))
+	}
+	if msg != expectedSyslog(t) {
+		t.Errorf("received %q, want %q", msg, expectedSyslog(t))
+	}
+}
+
+func TestSendSyslogInvalidAddress(t *testing.T) {
+	for _, address := range []string{"localhost:514", "http://localhost:514"} {
+		if err := sendSyslog(syslogConfig{Address: address}, syslogTestEvent); err == nil {
+			t.Errorf("sendSyslog(%q) succeeded", address)
+		}
+	}
+}
+
+func expectedSyslog(t *testing.T) string {
+	t.Helper()
+	msg, err := syslogMessage("", syslogTestEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// expectDatagram reads one message and compares it with the event.
+func expectDatagram(t *testing.T, conn net.PacketConn) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != expectedSyslog(t) {
+		t.Errorf("received %q, want %q", got, expectedSyslog(t))
+	}
+}
+
+// acceptAll returns everything written on the first connection.
+func acceptAll(listener net.Listener) chan string {
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- ""
+			return
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+	return received
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	sdListenFdsStart = 3
+	journalSocket    = "/run/systemd/journal/socket"
+)
+
+const serviceUnit = `[Unit]
+Description=Pomodoro timer
+Requires=pomodoro.socket
+After=pomodoro.socket
+
+[Service]
+Type=notify
+ExecStart=%s daemon
+WorkingDirectory=%s
+WatchdogSec=30
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`
+
+const socketUnit = `[Unit]
+Description=Pomodoro timer control socket
+
+[Socket]
+ListenStream=%t/pomodoro.sock
+SocketMode=0600
+
+[Install]
+WantedBy=sockets.target
+`
+
+// activatedListener returns the control socket passed by systemd socket
+// activation, or nil when the process was started some other way.
+func activatedListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	file := os.NewFile(sdListenFdsStart, "pomodoro.sock")
+	defer file.Close()
+
+	return net.FileListener(file)
+}
+
+// readyMsg tells the service manager the timer is ready once the event
+// loop handles it, so control requests are answered from then on.
+type readyMsg struct{}
+
+func readyCmd() tea.Msg {
+	return readyMsg{}
+}
+
+// socketUnitActive reports whether systemd listens on the control socket
+// for the daemon.
+func socketUnitActive() bool {
+	return exec.Command("systemctl", "--user", "is-active", "--quiet", "pomodoro.socket").Run() == nil
+}
+
+// sdNotify sends a state change such as "READY=1" to the service manager.
+// It does nothing when not running under systemd.
+func sdNotify(state string) error {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" {
+		return nil
+	}
+
+	// A leading '@' denotes a socket in the abstract namespace.
+	if strings.HasPrefix(socketPath, "@") {
+		socketPath = "\x00" + socketPath[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// watchdogInterval returns how often the service manager expects a watchdog
+// ping, or zero when the watchdog is disabled.
+func watchdogInterval() time.Duration {
+	usec, err := strconv.Atoi(os.Getenv("WATCHDOG_USEC"))
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	pid := os.Getenv("WATCHDOG_PID")
+	if pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+// runWatchdog pings the service manager while the event loop keeps
+// answering, so a hung timer gets restarted.
+func runWatchdog(control controller, interval time.Duration) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_, err := control.send(controlMsg{action: "ping"})
+		if err != nil {
+			continue
+		}
+		sdNotify("WATCHDOG=1")
+	}
+}
+
+// journalCmd writes a lifecycle event with structured fields to the journal
+// when the process runs as a systemd service.
+func journalCmd(event lifecycleEvent) tea.Cmd {
+	if os.Getenv("JOURNAL_STREAM") == "" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		fields := [][2]string{
+			{"MESSAGE", fmt.Sprintf("%s session %s", event.SessionType, event.Kind)},
+			{"PRIORITY", "6"},
+			{"SYSLOG_IDENTIFIER", "pomodoro"},
+			{"POMODORO_EVENT", event.name()},
+			{"POMODORO_SESSION_TYPE", event.SessionType},
+			{"POMODORO_DURATION_SEC", strconv.Itoa(int(event.Duration.Seconds()))},
+		}
+		if event.Preset != "" {
+			fields = append(fields, [2]string{"POMODORO_PRESET", event.Preset})
+		}
+		if event.Project != "" {
+			fields = append(fields, [2]string{"POMODORO_PROJECT", event.Project})
+		}
+		if event.Mark != "" {
+			fields = append(fields, [2]string{"POMODORO_MARK", event.Mark})
+		}
+
+		// Errors are ignored: the journal is best effort.
+		_ = traced("journal", func() error { return journalSend(fields) })
+		return nil
+	}
+}
+
+func journalSend(fields [][2]string) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	data := ""
+	for _, field := range fields {
+		data += field[0] + "=" + strings.ReplaceAll(field[1], "\n", " ") + "\n"
+	}
+
+	_, err = conn.Write([]byte(data))
+	return err
+}
+
+// systemdEscape keeps specifiers such as %h from being expanded in a unit
+// file value.
+func systemdEscape(value string) string {
+	return strings.ReplaceAll(value, "%", "%%")
+}
+
+// systemdQuote quotes one word of a command line in a unit file, so paths
+// with spaces, quotes, "%" or "$" are passed as they are.
+func systemdQuote(word string) string {
+	word = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(word)
+	return `"` + word + `"`
+}
+
+func installService(args []string) error {
+	flags := newFlagSet("install-service")
+	dir := flags.String("dir", "", "directory to write the units to (default ~/.config/systemd/user)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return err
+		}
+		*dir = filepath.Join(configDir, "systemd", "user")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	service := fmt.Sprintf(serviceUnit, systemdQuote(executable), systemdEscape(workDir))
+	err = os.WriteFile(filepath.Join(*dir, "pomodoro.service"), []byte(service), 0644)
+	if err != nil {
+		return fmt.Errorf("Error writing service unit: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(*dir, "pomodoro.socket"), []byte(socketUnit), 0644)
+	if err != nil {
+		return fmt.Errorf("Error writing socket unit: %v", err)
+	}
+
+	fmt.Printf("Installed pomodoro.service and pomodoro.socket in %s\n", *dir)
+	fmt.Println("Enable them with:")
+	fmt.Println("  systemctl --user daemon-reload")
+	fmt.Println("  systemctl --user enable --now pomodoro.socket")
+	return nil
+}
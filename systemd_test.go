@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadySentFromEventLoop(t *testing.T) {
+	chdirTemp(t)
+	path := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	m := initialModel()
+	m.headless = true
+	m.service = &timerService{}
+	program := tea.NewProgram(m, tea.WithInput(nil), tea.WithoutRenderer())
+	done := make(chan struct{})
+	go func() {
+		program.Run()
+		close(done)
+	}()
+	defer func() {
+		program.Quit()
+		<-done
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != "READY=1" {
+		t.Errorf("notified %q, want READY=1", got)
+	}
+
+	// The event loop that sent it answers control requests.
+	status, err := newController(program).send(controlMsg{action: "status"})
+	if err != nil || status.State != "idle" {
+		t.Errorf("status = %v, %v, want idle", status, err)
+	}
+}
+
+func TestControllerRefusesWhenBusy(t *testing.T) {
+	// A controller whose queue is full, as when the event loop hangs.
+	c := controller{requests: make(chan controlMsg)}
+
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if _, err := c.send(controlMsg{action: "ping"}); err == nil {
+			t.Fatal("send() to a hung timer succeeded")
+		}
+	}
+	if leaked := runtime.NumGoroutine() - goroutines; leaked > 0 {
+		t.Errorf("100 refused requests left %d goroutines behind", leaked)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/usr/bin/pomodoro", `"/usr/bin/pomodoro"`},
+		{"/home/me/My Tools/pomodoro", `"/home/me/My Tools/pomodoro"`},
+		{`/opt/a"b\c`, `"/opt/a\"b\\c"`},
+		{"/opt/100%/$HOME", `"/opt/100%%/$$HOME"`},
+	}
+	for _, test := range tests {
+		if got := systemdQuote(test.in); got != test.want {
+			t.Errorf("systemdQuote(%q) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
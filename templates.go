@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var defaultTemplates = map[string]string{
+	"session": `{{.Label}}: {{human .Duration}} from {{clock .StartTime}} to {{clock .EndTime}}` +
+		`{{if .Project}} @{{.Project}}{{end}}`,
+	"day_header": `{{if .Today}}Today's Completed Sessions:{{else}}Completed sessions on {{date .Date}}:{{end}}
+{{if .Labels}}Labels: {{join .Labels ", "}}
+{{end}}{{range .Notes}}Journal: {{.}}
+{{end}}`,
+	"summary": `{{.Count}} {{if eq .Count 1}}pomodoro{{else}}pomodoros{{end}}, {{human .Focus}} of focus
+{{- if or .Standing .Sitting}}
+Standing {{minutes .Standing}} · Sitting {{minutes .Sitting}}{{end}}`,
+	"status": `{{if eq .State "idle"}}No session running{{else}}{{.SessionType}} session {{.State}}, ` +
+		`{{human .Remaining}} left{{end}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"human":   humanizeDuration,
+	"minutes": formatMinutes,
+	"clock": func(t time.Time) string {
+		return t.Local().Format("15:04")
+	},
+	"date": func(t time.Time) string {
+		return t.Format(time.DateOnly)
+	},
+	"format": func(layout string, t time.Time) string {
+		return t.Local().Format(layout)
+	},
+	"join": strings.Join,
+	"color": func(color string, text string) string {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(text)
+	},
+	"bold": func(text string) string {
+		return lipgloss.NewStyle().Bold(true).Render(text)
+	},
+}
+
+// outputTemplates render the session list and the timer status.
+type outputTemplates struct {
+	session   *template.Template
+	dayHeader *template.Template
+	summary   *template.Template
+	status    *template.Template
+}
+
+type sessionLine struct {
+	session
+	Label string
+}
+
+type dayHeaderData struct {
+	Date   time.Time
+	Today  bool
+	Labels []string
+	Notes  []string
+}
+
+type summaryData struct {
+	Count    int
+	Focus    time.Duration
+	Standing time.Duration
+	Sitting  time.Duration
+}
+
+// loadTemplates parses the templates configured for "session",
+// "day_header", "summary" and "status", falling back to the defaults.
+func loadTemplates(configured map[string]string) (outputTemplates, error) {
+	parse := func(name string) (*template.Template, error) {
+		text, ok := configured[name]
+		if !ok {
+			text = defaultTemplates[name]
+		}
+		return parseTemplate(name, resolveTemplate(configured, text))
+	}
+
+	result := outputTemplates{}
+	targets := map[string]**template.Template{
+		"session":    &result.session,
+		"day_header": &result.dayHeader,
+		"summary":    &result.summary,
+		"status":     &result.status,
+	}
+
+	// Parse in name order so the same error is reported every time.
+	names := []string{}
+	for name := range targets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var firstErr error
+	for _, name := range names {
+		target := targets[name]
+		tmpl, err := parse(name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			tmpl, _ = parseTemplate(name, defaultTemplates[name])
+		}
+		*target = tmpl
+	}
+
+	return result, firstErr
+}
+
+// resolveTemplate returns the configured template called text, or text
+// itself when it is not the name of one.
+func resolveTemplate(configured map[string]string, text string) string {
+	if named, ok := configured[text]; ok {
+		return named
+	}
+	return text
+}
+
+func parseTemplate(name string, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s template: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+func execTemplate(tmpl *template.Template, data any) string {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return fmt.Sprintf("Error rendering %s template: %v", tmpl.Name(), err)
+	}
+	return buf.String()
+}
+
+// humanizeDuration spells out whole minutes, e.g. "1 hour 5 minutes".
+func humanizeDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	hours := minutes / 60
+	minutes %= 60
+
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	switch {
+	case hours == 0:
+		return plural(minutes, "minute")
+	case minutes == 0:
+		return plural(hours, "hour")
+	}
+	return plural(hours, "hour") + " " + plural(minutes, "minute")
+}
+
+// templatesFor loads the configured templates, with override replacing
+// the template called name when set.
+func templatesFor(cfg config, name string, override string) (outputTemplates, error) {
+	configured := map[string]string{}
+	for key, value := range cfg.Templates {
+		configured[key] = value
+	}
+	if override != "" {
+		configured[name] = resolveTemplate(cfg.Templates, override)
+	}
+	return loadTemplates(configured)
+}
+
+func runList(args []string) error {
+	flags := newFlagSet("list")
+	override := flags.String("template", "", "session line template, or the name of one in config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	date := time.Now()
+	if flags.NArg() > 0 {
+		parsed, err := time.ParseInLocation(time.DateOnly, flags.Arg(0), time.Local)
+		if err != nil {
+			return fmt.Errorf("Invalid date format")
+		}
+		date = parsed
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := templatesFor(cfg, "session", *override)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := loadSessions()
+	if err != nil {
+		return err
+	}
+
+	days, err := loadDays()
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(printSessions(newSessionIndex(sessions), days, tmpl, flags.NArg() > 0, date))
+	return nil
+}
+
+func runStatus(args []string) error {
+	flags := newFlagSet("status")
+	override := flags.String("template", "", "status template, or the name of one in config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := templatesFor(cfg, "status", *override)
+	if err != nil {
+		return err
+	}
+
+	// Without a running timer the status is simply idle.
+	status := timerStatus{State: "idle"}
+	reply, err := sendControlLine("status")
+	if err == nil {
+		status = parseStatus(reply)
+	}
+
+	fmt.Println(execTemplate(tmpl.status, status))
+	return nil
+}
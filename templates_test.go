@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTemplatesDefaults(t *testing.T) {
+	tmpl, err := loadTemplates(nil)
+	if err != nil {
+		t.Fatalf("loadTemplates(nil) = %v", err)
+	}
+
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	line := sessionLine{
+		session: session{StartTime: start, EndTime: start.Add(25 * time.Minute), Duration: 25 * time.Minute, Project: "api"},
+		Label:   "Work",
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"session", execTemplate(tmpl.session, line), "Work: 25 minutes from 09:00 to 09:25 @api"},
+		{"summary", execTemplate(tmpl.summary, summaryData{Count: 1, Focus: 90 * time.Minute}), "1 pomodoro, 1 hour 30 minutes of focus"},
+		{"idle status", execTemplate(tmpl.status, timerStatus{State: "idle"}), "No session running"},
+		{
+			"running status",
+			execTemplate(tmpl.status, timerStatus{State: "running", SessionType: workSession, Remaining: 12 * time.Minute}),
+			workSession + " session running, 12 minutes left",
+		},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s = %q, want %q", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestLoadTemplatesConfigured(t *testing.T) {
+	tmpl, err := loadTemplates(map[string]string{
+		"short":   "{{.Label}} {{minutes .Duration}}",
+		"session": "short",
+		"status":  `{{.State}}{{if ne .State "idle"}} {{human .Remaining}}{{end}}`,
+	})
+	if err != nil {
+		t.Fatalf("loadTemplates() = %v", err)
+	}
+
+	line := sessionLine{session: session{Duration: 25 * time.Minute}, Label: "Work"}
+	if got, want := execTemplate(tmpl.session, line), "Work "+formatMinutes(25*time.Minute); got != want {
+		t.Errorf("session by name = %q, want %q", got, want)
+	}
+	if got := execTemplate(tmpl.status, timerStatus{State: "paused", Remaining: time.Hour}); got != "paused 1 hour" {
+		t.Errorf("status = %q, want %q", got, "paused 1 hour")
+	}
+}
+
+func TestLoadTemplatesReportsErrorsInOrder(t *testing.T) {
+	configured := map[string]string{
+		"status":     "{{.State",
+		"summary":    "{{.Count",
+		"day_header": "{{range}}",
+	}
+
+	for i := 0; i < 20; i++ {
+		tmpl, err := loadTemplates(configured)
+		if err == nil || !strings.Contains(err.Error(), "day_header template") {
+			t.Fatalf("loadTemplates() = %v, want the day_header error first", err)
+		}
+		if got := execTemplate(tmpl.status, timerStatus{State: "idle"}); got != "No session running" {
+			t.Fatalf("broken status template = %q, want the default", got)
+		}
+	}
+}
+
+func TestTemplatesForOverride(t *testing.T) {
+	cfg := config{Templates: map[string]string{"bare": "{{.Label}}", "session": "{{.Label}}!"}}
+
+	tests := []struct {
+		override string
+		want     string
+	}{
+		{"", "Work!"},
+		{"bare", "Work"},
+		{"{{.Label}}?", "Work?"},
+	}
+	for _, test := range tests {
+		tmpl, err := templatesFor(cfg, "session", test.override)
+		if err != nil {
+			t.Fatalf("templatesFor(%q) = %v", test.override, err)
+		}
+		if got := execTemplate(tmpl.session, sessionLine{Label: "Work"}); got != test.want {
+			t.Errorf("templatesFor(%q) renders %q, want %q", test.override, got, test.want)
+		}
+	}
+}
+
+func TestExecTemplateError(t *testing.T) {
+	tmpl, err := parseTemplate("session", "{{.Missing}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := execTemplate(tmpl, sessionLine{}); !strings.HasPrefix(got, "Error rendering session template") {
+		t.Errorf("execTemplate() with a missing field = %q, want the error", got)
+	}
+}
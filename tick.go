@@ -0,0 +1,142 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// blurredTick is the longest the clock sleeps while the terminal is
+	// not focused. It still wakes for marks and the end of a session.
+	blurredTick = time.Minute
+	// tickSlack lets a tick land just after a whole second so rounding
+	// never shows the same second twice.
+	tickSlack = 10 * time.Millisecond
+	// closingTime is how long the finished screen stays up.
+	closingTime = 4 * time.Second
+	// frameRate caps how often Bubble Tea's renderer wakes to draw. Its
+	// frame ticker runs even while the timer sleeps and cannot be paused,
+	// so it runs at a quarter of the default 60 frames per second, still
+	// quick enough to echo typing.
+	frameRate = 15
+)
+
+// tickMsg carries the id of the clock that scheduled it; ticks from a
+// clock that was stopped or restarted since are dropped.
+//
+// Bubble Tea's renderer writes at most one frame per frame interval,
+// however many messages arrive, and skips the write when the frame equals
+// the last one drawn, so a tick that changes nothing on screen costs a View
+// call but no terminal output.
+type tickMsg struct{ id int }
+
+// clockRunning tells whether anything on screen is counting.
+func (m model) clockRunning() bool {
+	if m.agenda.active() {
+		return !m.agenda.finished
+	}
+	return m.inSession && !m.paused && !m.askingPosture && !m.askingIntent && !m.reviewing
+}
+
+// syncClock starts ticking when a countdown starts or resumes and stops
+// when it is paused, waits for input or is over, so an idle timer never
+// wakes up.
+func (m *model) syncClock() tea.Cmd {
+	running := m.clockRunning()
+	switch {
+	case running && !m.ticking:
+		m.ticking = true
+		m.tickID++
+		if !m.agenda.active() {
+			m.deadline = time.Now().Add(m.remainingTime)
+		}
+		return m.tickCmd()
+	case !running && m.ticking:
+		m.ticking = false
+		m.tickID++
+		if m.inSession {
+			m.remainingTime = m.untilDeadline()
+		}
+	}
+	return nil
+}
+
+// restartClock makes the next syncClock start over from remainingTime.
+func (m *model) restartClock() {
+	m.ticking = false
+	m.tickID++
+}
+
+func (m model) untilDeadline() time.Duration {
+	return time.Until(m.deadline).Round(time.Second)
+}
+
+// tickCmd wakes on the next whole second of the deadline, so the seconds
+// shown change exactly when they should, or later while blurred.
+func (m model) tickCmd() tea.Cmd {
+	id := m.tickID
+	return tea.Tick(m.tickDelay(time.Now())+tickSlack, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
+	})
+}
+
+// tickDelay is how long after now the clock next wakes, before tickSlack.
+func (m model) tickDelay(now time.Time) time.Duration {
+	anchor := m.deadline
+	if m.agenda.active() {
+		anchor = m.agenda.itemStarted
+	}
+
+	delay := anchor.Sub(now) % time.Second
+	if delay <= 0 {
+		delay += time.Second
+	}
+	if m.blurred {
+		delay = m.blurredDelay(delay, now)
+	}
+	return delay
+}
+
+// blurredDelay stretches delay up to the next moment something happens.
+func (m model) blurredDelay(delay time.Duration, now time.Time) time.Duration {
+	events := []time.Time{}
+	if m.agenda.active() {
+		if !m.agenda.warned {
+			item := m.agenda.items[m.agenda.current]
+			events = append(events, m.agenda.itemStarted.Add(item.planned+time.Second))
+		}
+	} else {
+		events = append(events, m.deadline.Add(-m.timerDuration), m.deadline, m.deadline.Add(closingTime))
+		for _, mark := range m.marks {
+			if !mark.fired {
+				events = append(events, m.deadline.Add(-mark.remaining))
+			}
+		}
+	}
+
+	wake := now.Add(blurredTick)
+	for _, event := range events {
+		if event.After(now) && event.Before(wake) {
+			wake = event
+		}
+	}
+	return max(wake.Sub(now), delay)
+}
+
+// focusChanged switches between ticking every second and only when
+// something happens.
+func (m *model) focusChanged(focused bool) tea.Cmd {
+	m.blurred = !focused
+
+	cmds := []tea.Cmd{}
+	if m.ticking {
+		m.tickID++
+		cmds = append(cmds, m.tickCmd())
+	}
+	if m.sideTicking {
+		m.sideTickID++
+		cmds = append(cmds, m.sideTickCmd())
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickDelay(t *testing.T) {
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	session := func(left time.Duration, blurred bool, marks ...sessionMark) model {
+		return model{inSession: true, deadline: now.Add(left), timerDuration: 25 * time.Minute,
+			blurred: blurred, marks: marks}
+	}
+
+	tests := []struct {
+		name string
+		m    model
+		want time.Duration
+	}{
+		{"next whole second", session(10*time.Minute+300*time.Millisecond, false), 300 * time.Millisecond},
+		{"on a whole second", session(10*time.Minute, false), time.Second},
+		{"closing", session(-1500*time.Millisecond, false), 500 * time.Millisecond},
+		{"blurred", session(10*time.Minute+300*time.Millisecond, true), blurredTick},
+		{"blurred before the end", session(20*time.Second, true), 20 * time.Second},
+		{"blurred after the end", session(-time.Second, true), closingTime - time.Second},
+		{"blurred before a mark", session(10*time.Minute, true, sessionMark{remaining: 9*time.Minute + 30*time.Second}),
+			30 * time.Second},
+		{"blurred past a fired mark", session(10*time.Minute, true,
+			sessionMark{remaining: 9*time.Minute + 30*time.Second, fired: true}), blurredTick},
+		{"blurred mark due now", session(10*time.Minute+200*time.Millisecond, true,
+			sessionMark{remaining: 10 * time.Minute}), 200 * time.Millisecond},
+		{"agenda", model{agenda: agendaRun{items: []agendaItem{{planned: time.Minute}},
+			itemStarted: now.Add(-2250 * time.Millisecond)}}, 750 * time.Millisecond},
+		{"blurred agenda", model{blurred: true, agenda: agendaRun{items: []agendaItem{{planned: time.Minute}},
+			itemStarted: now.Add(-20 * time.Second)}}, 41 * time.Second},
+	}
+	for _, test := range tests {
+		if got := test.m.tickDelay(now); got != test.want {
+			t.Errorf("%s: tickDelay() = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSideTickDelay(t *testing.T) {
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		blurred bool
+		timers  []sideTimer
+		want    time.Duration
+	}{
+		{"focused", false, []sideTimer{{deadline: now.Add(time.Hour)}}, time.Second},
+		{"blurred", true, []sideTimer{{deadline: now.Add(time.Hour)}}, blurredTick},
+		{"blurred before a timer ends", true, []sideTimer{{deadline: now.Add(time.Hour)}, {deadline: now.Add(5 * time.Second)}},
+			5 * time.Second},
+		{"blurred while a finished timer lingers", true, []sideTimer{{deadline: now, done: true}}, sideTimerLinger},
+	}
+	for _, test := range tests {
+		m := model{blurred: test.blurred, timers: test.timers}
+		if got := m.sideTickDelay(now); got != test.want {
+			t.Errorf("%s: sideTickDelay() = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFocusChanged(t *testing.T) {
+	m := model{inSession: true, ticking: true, tickID: 4, deadline: time.Now().Add(time.Hour)}
+
+	if cmd := m.focusChanged(false); cmd == nil || !m.blurred || m.tickID != 5 {
+		t.Errorf("blur: cmd = %v, blurred = %v, tickID = %d, want a new tick with id 5", cmd != nil, m.blurred, m.tickID)
+	}
+	if cmd := m.focusChanged(true); cmd == nil || m.blurred || m.tickID != 6 {
+		t.Errorf("focus: cmd = %v, blurred = %v, tickID = %d, want a new tick with id 6", cmd != nil, m.blurred, m.tickID)
+	}
+
+	// Ticks scheduled before the change are dropped.
+	next, cmd := m.update(tickMsg{id: 5})
+	if cmd != nil || next.(model).tickID != 6 {
+		t.Error("a tick from before the focus change was handled")
+	}
+
+	idle := model{}
+	if cmd := idle.focusChanged(false); cmd != nil {
+		t.Error("focusChanged() schedules a tick while idle")
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const announceQueueSize = 16
+
+var defaultPhrases = map[string]string{
+	"work_start":     "Focus for {{.Minutes}} minutes{{if .Project}} on {{.Project}}{{end}}.",
+	"work_complete":  "Work session complete. Time for a break.",
+	"break_complete": "Break over, back to work{{if .Project}} on {{.Project}}{{end}}.",
+	"work_mark":      "{{.Mark}}.",
+	"break_mark":     "{{.Mark}}.",
+}
+
+type phraseData struct {
+	Event       string
+	SessionType string
+	Project     string
+	Preset      string
+	Mark        string
+	Minutes     int
+}
+
+// announcer speaks queued phrases one at a time so they never overlap.
+// The queue is made up front since silence may run on another goroutine
+// before anything was said; the speaking goroutine starts with the first
+// announcement.
+type announcer struct {
+	once    sync.Once
+	queue   chan announcement
+	mu      sync.Mutex
+	current *exec.Cmd
+	epoch   int // bumped by silence to drop what was queued before
+}
+
+type announcement struct {
+	command []string
+	stdin   bool
+	text    string
+	epoch   int
+}
+
+var speech = newAnnouncer()
+
+func newAnnouncer() *announcer {
+	return &announcer{queue: make(chan announcement, announceQueueSize)}
+}
+
+func (a *announcer) say(item announcement) error {
+	a.once.Do(func() { go a.run() })
+
+	a.mu.Lock()
+	item.epoch = a.epoch
+	a.mu.Unlock()
+
+	select {
+	case a.queue <- item:
+		return nil
+	default:
+		return fmt.Errorf("too many pending announcements")
+	}
+}
+
+func (a *announcer) run() {
+	for item := range a.queue {
+		args := item.command[1:]
+		if !item.stdin {
+			args = append(args[:len(args):len(args)], item.text)
+		}
+
+		cmd := exec.Command(item.command[0], args...)
+		if item.stdin {
+			cmd.Stdin = strings.NewReader(item.text)
+		}
+
+		a.mu.Lock()
+		if item.epoch != a.epoch {
+			a.mu.Unlock()
+			continue
+		}
+		err := cmd.Start()
+		if err != nil {
+			a.mu.Unlock()
+			continue
+		}
+		a.current = cmd
+		a.mu.Unlock()
+
+		cmd.Wait()
+
+		a.mu.Lock()
+		a.current = nil
+		a.mu.Unlock()
+	}
+}
+
+// silence stops the phrase being spoken and drops the pending ones.
+func (a *announcer) silence() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.epoch++
+	for len(a.queue) > 0 {
+		select {
+		case <-a.queue:
+		default:
+		}
+	}
+
+	if a.current != nil && a.current.Process != nil {
+		a.current.Process.Kill()
+	}
+}
+
+func ttsCmd(cfg ttsConfig, event lifecycleEvent, muted bool) tea.Cmd {
+	if !cfg.Enabled || muted || len(cfg.Command) == 0 {
+		return nil
+	}
+	if inQuietHours(cfg.QuietHours, event.Time) {
+		return nil
+	}
+
+	phrase, ok := cfg.Phrases[event.name()]
+	if !ok {
+		phrase, ok = defaultPhrases[event.name()]
+	}
+	if !ok || phrase == "" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		text, err := renderPhrase(phrase, phraseData{
+			Event:       event.name(),
+			SessionType: event.SessionType,
+			Project:     event.Project,
+			Preset:      event.Preset,
+			Mark:        event.Mark,
+			Minutes:     int(event.Duration.Minutes()),
+		})
+		if err == nil {
+			err = traced("tts", func() error {
+				return speech.say(announcement{command: cfg.Command, stdin: cfg.Stdin, text: text})
+			})
+		}
+		if err != nil {
+			return integrationErrMsg{fmt.Errorf("Error announcing %s: %v", event.name(), err)}
+		}
+		return nil
+	}
+}
+
+func renderPhrase(phrase string, data phraseData) (string, error) {
+	tmpl, err := template.New("phrase").Parse(phrase)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// inQuietHours reports whether t falls in the "HH:MM" window, which may
+// wrap around midnight.
+func inQuietHours(window quietHours, t time.Time) bool {
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", window.End)
+	if err != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingCommand appends each phrase to a file, taking a moment so
+// phrases would overlap if they were not queued.
+func recordingCommand(t *testing.T) ([]string, string) {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "spoken")
+	return []string{"sh", "-c", `sleep 0.05; echo "$0" >> ` + out}, out
+}
+
+func readSpoken(t *testing.T, out string) []string {
+	t.Helper()
+	data, err := os.ReadFile(out)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestAnnouncerSpeaksInOrder(t *testing.T) {
+	command, out := recordingCommand(t)
+	a := newAnnouncer()
+	for _, text := range []string{"one", "two", "three"} {
+		if err := a.say(announcement{command: command, text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(readSpoken(t, out)) < 3 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := strings.Join(readSpoken(t, out), " "); got != "one two three" {
+		t.Errorf("spoken %q, want one two three", got)
+	}
+}
+
+func TestAnnouncerSilenceDropsQueued(t *testing.T) {
+	command, out := recordingCommand(t)
+	a := newAnnouncer()
+
+	// Muting before anything was said must not race with the first say.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); a.silence() }()
+	go func() { defer wg.Done(); a.say(announcement{command: []string{"true"}}) }()
+	wg.Wait()
+
+	for _, text := range []string{"one", "two", "three"} {
+		a.say(announcement{command: command, text: text})
+	}
+	a.silence()
+	a.say(announcement{command: command, text: "after"})
+
+	time.Sleep(500 * time.Millisecond)
+	for _, text := range readSpoken(t, out) {
+		if text == "two" || text == "three" {
+			t.Errorf("%q was spoken after silence()", text)
+		}
+	}
+	if spoken := readSpoken(t, out); len(spoken) == 0 || spoken[len(spoken)-1] != "after" {
+		t.Errorf("spoken %v, want the announcement made after silence()", spoken)
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, _ := time.Parse("15:04", clock)
+		return parsed
+	}
+	night := quietHours{Start: "22:00", End: "07:00"}
+	lunch := quietHours{Start: "12:00", End: "13:00"}
+
+	tests := []struct {
+		window quietHours
+		clock  string
+		want   bool
+	}{
+		{night, "23:30", true},
+		{night, "06:59", true},
+		{night, "07:00", false},
+		{night, "12:00", false},
+		{lunch, "12:30", true},
+		{lunch, "13:00", false},
+		{quietHours{}, "03:00", false},
+	}
+	for _, test := range tests {
+		if got := inQuietHours(test.window, at(test.clock)); got != test.want {
+			t.Errorf("inQuietHours(%v, %s) = %v, want %v", test.window, test.clock, got, test.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 )
 
 type model struct {
@@ -15,6 +16,8 @@ type model struct {
 	remainingTime      time.Duration
 	startTime          time.Time
 	inSession          bool
+	paused             bool
+	pauses             int
 	sessionType        string // "Work" or "Break"
 	keys               keyMap
 	opening            bool
@@ -23,17 +26,175 @@ type model struct {
 	printDifferentDate bool
 	datePrint          time.Time
 	textarea           textarea.Model
-	err                string
 	sessions           []session
+	config             config
+	preset             string
+	project            string
+	tags               []string
+	muted              bool
+	marks              []sessionMark
+	highlight          string
+	highlightUntil     time.Time
+	sequence           sequenceRun
+	agenda             agendaRun
+	timers             []sideTimer
+	sideTicking        bool
+	sideTickID         int
+	prompting          bool
+	headless           bool
+	askingIntent       bool
+	reviewing          bool
+	intent             string
+	completedAt        time.Time
+	showIntents        bool
+	showCalendar       bool
+	calendarMonth      time.Time
+	days               map[string]dayEntry
+	templates          outputTemplates
+	plan               []planItem
+	loading            bool
+	loadErr            error
+	loadRetryIn        time.Duration
+	saves              map[string]*saveState
+	index              sessionIndex
+	deadline           time.Time
+	ticking            bool
+	tickID             int
+	blurred            bool
+	gaugeSentAt        time.Time
+	toasts             []toast
+	toastWaking        bool
+	messages           []message
+	showMessages       bool
+	logView            viewport.Model
+	debug              bool
+	rest               restCalendar
+	posture            string
+	askingPosture      bool
+	commandLine        bool
+	showHelp           bool
+	service            *timerService
 }
 
 type keyMap struct {
-	Stop key.Binding
-	Quit key.Binding
+	Stop  key.Binding
+	Quit  key.Binding
+	Pause key.Binding
+	Mute  key.Binding
+	Next  key.Binding
+	Timer key.Binding
+	Copy  key.Binding
 }
 
 type session struct {
 	StartTime time.Time     `json:"start_time"`
 	EndTime   time.Time     `json:"end_time"`
 	Duration  time.Duration `json:"duration"`
+	Pauses    int           `json:"pauses,omitempty"`
+	Project   string        `json:"project,omitempty"`
+	Tags      []string      `json:"tags,omitempty"`
+	Type      string        `json:"type,omitempty"`
+	Sequence  string        `json:"sequence,omitempty"`
+	Step      int           `json:"step,omitempty"`
+	Intent    string        `json:"intent,omitempty"`
+	Outcome   string        `json:"outcome,omitempty"` // "yes", "partly" or "no"
+	Posture   string        `json:"posture,omitempty"` // "sitting" or "standing"
+}
+
+// isWork reports whether s is a pomodoro. Sessions saved before the type
+// was recorded are all work sessions.
+func (s session) isWork() bool {
+	return s.Type == "" || s.Type == workSession
+}
+
+// completedAt returns when s was completed. Sessions saved before end times
+// were recorded are taken to have run without pauses.
+func (s session) completedAt() time.Time {
+	if s.EndTime.IsZero() {
+		return s.StartTime.Add(s.Duration)
+	}
+	return s.EndTime
+}
+
+type config struct {
+	Presets       map[string]preset   `json:"presets"`
+	Sequences     map[string]string   `json:"sequences"`
+	AskIntent     bool                `json:"ask_intent"`
+	Standup       standupConfig       `json:"standup"`
+	DailyGoal     int                 `json:"daily_goal"`
+	RestDays      []string            `json:"rest_days"`
+	Vacations     []vacation          `json:"vacations"`
+	Posture       string              `json:"posture"` // "ask" or "alternate"
+	Mode          string              `json:"mode"`    // "command" (default) or "normal"
+	Templates     map[string]string   `json:"templates"`
+	Marks         map[string][]string `json:"marks"`
+	Notify        []string            `json:"notify"`
+	Sound         []string            `json:"sound"`
+	Hooks         map[string][]string `json:"hooks"`
+	MPRIS         mprisConfig         `json:"mpris"`
+	DBus          dbusConfig          `json:"dbus"`
+	TTS           ttsConfig           `json:"tts"`
+	Syslog        syslogConfig        `json:"syslog"`
+	Statsd        statsdConfig        `json:"statsd"`
+	Influx        influxConfig        `json:"influx"`
+	SummaryFormat string              `json:"summary_format"` // "plain" (default) or "markdown"
+}
+
+type preset struct {
+	Work     int      `json:"work"`
+	Break    int      `json:"break"`
+	Playlist string   `json:"playlist"`
+	Marks    []string `json:"marks"`
+}
+
+type mprisConfig struct {
+	Enabled bool              `json:"enabled"`
+	Player  string            `json:"player"`
+	Actions map[string]string `json:"actions"`
+}
+
+type ttsConfig struct {
+	Enabled    bool              `json:"enabled"`
+	Command    []string          `json:"command"`
+	Stdin      bool              `json:"stdin"`
+	Phrases    map[string]string `json:"phrases"`
+	QuietHours quietHours        `json:"quiet_hours"`
+}
+
+type quietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type standupConfig struct {
+	Template string `json:"template"`
+}
+
+type dbusConfig struct {
+	Disabled bool `json:"disabled"`
+}
+
+// controlMsg asks the running model to act on behalf of an external client.
+// The outcome is sent back on reply.
+type controlMsg struct {
+	action      string // "start", "stop", "pause", "resume", "status" or "ping"
+	sessionType string
+	minutes     int
+	reply       chan controlReply
+}
+
+type controlReply struct {
+	status timerStatus
+	err    error
+}
+
+type timerStatus struct {
+	State       string
+	Remaining   time.Duration
+	SessionType string
+}
+
+// seconds is the remaining time as exported on the bus.
+func (s timerStatus) seconds() int64 {
+	return int64(s.Remaining.Seconds())
 }
@@ -2,8 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,18 +19,65 @@ func showHelper() string {
 
  - Press 's' to start work session.
           s <minutes> to start work session for <minutes> minutes
+          s <preset> to start work session with a preset from config
+          s <minutes> @<project> to work on <project>
+          s <minutes> #<tag> to tag the session, e.g. #review
 
  - Press 'b' to take a break.
           b <minutes> to take break for <minutes> minutes
+          b <preset> to take break with a preset from config
 
  - Press 'l' to list all completed today's sessions.
           l YYYY-MM-DD to list completed sessions on that date.
 
+ - Type 'run <steps>' to run a sequence, e.g. run 50w 10b 50w 10b 90w 30b
+          run <name> to run a sequence from config
+
+ - Press 'i' to see how often you achieved your intents.
+
+ - Press 'c' to show this month's calendar.
+          c YYYY-MM to show another month.
+
+ - Type 'label <label>' to label today, e.g. sick, travel, on-call, deep-work.
+          label <label> YYYY-MM-DD to label another day, again to remove it.
+          journal <text> to write a journal note for today.
+
+ - Type 'plan <task>' to plan a task for today's standup.
+
+ - Type 't <name> <duration>' to run a side timer, e.g. t deploy 10m
+          t <name> to remove it. Press 't' during a session.
+
+ - Type 'agenda <file>' to timebox a meeting agenda.
+          agenda 5 Intro; 15 Updates; 10 Q&A for an inline agenda.
+
+ - Press 'm' to mute or unmute spoken announcements.
+
+ - Type 'log' to scroll through earlier messages.
+
  - Press 'q' to quit.
 `
 	return helpText
 }
 
+// parseProject takes "@project" and "#tag" arguments out of a start
+// command and returns the rest of the command.
+func parseProject(m *model, command string) string {
+	fields := strings.Fields(command)
+	rest := []string{}
+	for _, field := range fields {
+		if strings.HasPrefix(field, "@") && len(field) > 1 {
+			m.project = field[1:]
+			continue
+		}
+		if strings.HasPrefix(field, "#") && len(field) > 1 {
+			m.tags = append(m.tags, field[1:])
+			continue
+		}
+		rest = append(rest, field)
+	}
+	return strings.Join(rest, " ")
+}
+
 func checkValidMinute(m *model, command string) (int, bool) {
 	if command == "s" || command == "b" {
 		return 0, true
@@ -34,33 +85,82 @@ func checkValidMinute(m *model, command string) (int, bool) {
 
 	spacing := command[1:]
 	if !strings.HasPrefix(spacing, " ") {
-		m.err = "Invalid command"
+		m.toast(slog.LevelWarn, "Invalid command")
 		return 0, false
 	}
 
 	numOfMinutesStr := strings.TrimSpace(command[2:])
 
+	if p, ok := m.config.Presets[numOfMinutesStr]; ok {
+		m.preset = numOfMinutesStr
+		if strings.HasPrefix(command, "b") {
+			return p.Break, true
+		}
+		return p.Work, true
+	}
+
 	numOfMinutes, err := strconv.Atoi(numOfMinutesStr)
 	if err != nil {
-		m.err = "Invalid number of minutes"
+		m.toast(slog.LevelWarn, "Invalid number of minutes")
 		return 0, false
 	}
 	return numOfMinutes, true
 }
 
-func loadSessions() []session {
-	data, err := os.ReadFile("db.json")
+func loadSessions() ([]session, error) {
+	sessions := []session{}
+	err := readJSON(sessionsFile, &sessions)
 	if err != nil {
-		return []session{}
+		return []session{}, err
 	}
 
-	sessions := []session{}
-	err = json.Unmarshal(data, &sessions)
+	return sessions, nil
+}
+
+// readJSON leaves v untouched when name does not exist yet, and returns a
+// corruptFileError when it cannot be parsed.
+func readJSON(name string, v any) error {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
-		return []session{}
+		return fmt.Errorf("Error reading %s: %v", name, err.Error())
 	}
 
-	return sessions
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		logger.Error("Error parsing data file", "file", name, "err", err)
+		return corruptFileError{file: name, err: err}
+	}
+
+	return nil
+}
+
+// corruptFileError is returned for a data file that exists but cannot be
+// parsed. Saving over it would lose whatever is still in it.
+type corruptFileError struct {
+	file string
+	err  error
+}
+
+func (e corruptFileError) Error() string {
+	return fmt.Sprintf("Error reading %s: %v", e.file, e.err)
+}
+
+func (e corruptFileError) Unwrap() error {
+	return e.err
+}
+
+// moveAside renames a corrupt file to e.g. db.json.corrupt-20240506-092500
+// so it can be repaired by hand, and returns the new name.
+func moveAside(name string) (string, error) {
+	aside := name + ".corrupt-" + time.Now().Format("20060102-150405")
+	err := os.Rename(name, aside)
+	if err != nil {
+		return "", err
+	}
+	return aside, nil
 }
 
 func saveSessions(sessions []session) error {
@@ -69,7 +169,7 @@ func saveSessions(sessions []session) error {
 		return fmt.Errorf("Error marshal data: %v", err.Error())
 	}
 
-	err = os.WriteFile("db.json", data, 0644)
+	err = writeFile(sessionsFile, data)
 	if err != nil {
 		return fmt.Errorf("Error writing to file: %v", err.Error())
 	}
@@ -77,48 +177,78 @@ func saveSessions(sessions []session) error {
 	return nil
 }
 
-func printSessions(sessions []session, differentDate bool, date time.Time) string {
-	printingResult := ""
-
-	if !differentDate {
-		today := time.Now()
-		todaySessions := getCorrectSession(sessions, today)
+// writeFile replaces name through a temporary file so a failed or
+// interrupted write never leaves it half written.
+func writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
 
-		printingResult = "Today's Completed Sessions:\n"
-		printingResult += printHelper(todaySessions)
-	} else {
-		differentDateSessions := getCorrectSession(sessions, date)
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
 
-		printingResult = fmt.Sprintf("Completed sessions on %v:\n", date.Format(time.DateOnly))
-		printingResult += printHelper(differentDateSessions)
+func printSessions(index sessionIndex, days map[string]dayEntry, tmpl outputTemplates,
+	differentDate bool, date time.Time) string {
+	if !differentDate {
+		date = time.Now()
 	}
 
+	entry := days[date.Format(time.DateOnly)]
+	printingResult := execTemplate(tmpl.dayHeader, dayHeaderData{
+		Date:   date,
+		Today:  !differentDate,
+		Labels: entry.Labels,
+		Notes:  entry.Notes,
+	})
+	printingResult += printHelper(index.day(date), tmpl)
+
 	return printingResult
 }
 
-func getCorrectSession(sessions []session, date time.Time) []session {
-	resultSessions := []session{}
+func printHelper(sessions []session, tmpl outputTemplates) string {
+	resultPrinting := ""
+	if len(sessions) == 0 {
+		return "\nYou haven't completed any session 😕\n"
+	}
+
+	summary := summaryData{}
 	for _, s := range sessions {
-		if s.StartTime.Add(s.Duration).Format(time.DateOnly) == date.Format(time.DateOnly) {
-			resultSessions = append(resultSessions, s)
+		label := "Pomodoro session"
+		if !s.isWork() {
+			label = "Break"
+		}
+		resultPrinting += execTemplate(tmpl.session, sessionLine{session: s, Label: label}) + "\n"
+
+		if s.isWork() {
+			summary.Count++
+			summary.Focus += s.Duration
 		}
 	}
 
-	return resultSessions
+	totals := postureTotals(sessions)
+	summary.Standing = totals[standing]
+	summary.Sitting = totals[sitting]
+
+	return resultPrinting + "\n" + execTemplate(tmpl.summary, summary) + "\n"
 }
 
-func printHelper(sessions []session) string {
-	resultPrinting := ""
-	if len(sessions) == 0 {
-		return "\nYou haven't completed any session 😕\n"
+// formatMinutes shows a duration in whole minutes, e.g. "1h 25m".
+func formatMinutes(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
 	}
-	for _, s := range sessions {
-		resultPrinting += fmt.Sprintf(
-			"Pomodoro session: duration of %f minutes from %v to %v\n",
-			s.Duration.Minutes(),
-			s.StartTime.Format("15:04"),
-			s.EndTime.Format("15:04"),
-		)
-	}
-	return resultPrinting
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
 }